@@ -0,0 +1,285 @@
+package logger_grpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gol4ng/logger"
+)
+
+// ErrAsyncLoggerClosed is returned when an entry is logged after the AsyncLogger is closed
+var ErrAsyncLoggerClosed = errors.New("async logger closed")
+
+// AsyncAction defines how an entry is queued by the AsyncLogger
+type AsyncAction int
+
+const (
+	// AsyncEnqueue queues the entry, it is dropped when the queue is full
+	AsyncEnqueue AsyncAction = iota
+	// AsyncBlock waits until the entry is queued
+	AsyncBlock
+	// AsyncDrop drops the entry
+	AsyncDrop
+)
+
+// DropPolicy function defines the AsyncAction of an entry given its level and the queue fill ratio (from 0 to 1)
+type DropPolicy func(level logger.Level, fill float64) AsyncAction
+
+// DefaultDropPolicy blocks for the error entries and the more severe ones,
+// the debug entries are dropped first once the queue is half full and the others once it is full
+func DefaultDropPolicy(level logger.Level, fill float64) AsyncAction {
+	switch {
+	case level <= logger.ErrorLevel:
+		return AsyncBlock
+	case level >= logger.DebugLevel && fill >= 0.5:
+		return AsyncDrop
+	}
+	return AsyncEnqueue
+}
+
+type asyncEntry struct {
+	message string
+	level   logger.Level
+	context *logger.Context
+	// flushed is closed by the worker when the entries queued before it are handled
+	flushed chan struct{}
+}
+
+// AsyncLogger is a logger.LoggerInterface queuing the entries to a bounded queue handled by the wrapped logger in its own goroutine,
+// so a slow log handler doesn't slow down the calls. The logger contexts are copied when the entries are queued.
+// The dropped entries are counted by level and logged periodically at warning level.
+type AsyncLogger struct {
+	logger             logger.LoggerInterface
+	queueSize          int
+	dropPolicy         DropPolicy
+	dropReportInterval time.Duration
+
+	mu          sync.RWMutex
+	closed      bool
+	closeHooks  map[*closeHook]struct{}
+	queue       chan asyncEntry
+	done        chan struct{}
+	stopReports func()
+	dropped     [logger.DebugLevel + 1]int64
+}
+
+const defaultAsyncQueueSize = 1024
+
+// AsyncOption customizes the AsyncLogger
+type AsyncOption func(*AsyncLogger)
+
+// WithAsyncQueueSize customizes the number of entries the AsyncLogger queue can hold, 1024 by default, a size lower than 1 keeps the default.
+func WithAsyncQueueSize(size int) AsyncOption {
+	return func(l *AsyncLogger) {
+		l.queueSize = size
+	}
+}
+
+// WithAsyncDropPolicy customizes the function for deciding if an entry is queued, dropped or waits for the queue.
+func WithAsyncDropPolicy(f DropPolicy) AsyncOption {
+	return func(l *AsyncLogger) {
+		l.dropPolicy = f
+	}
+}
+
+// WithAsyncDropReportInterval customizes the interval the dropped entries counters are logged at, 10 seconds by default.
+func WithAsyncDropReportInterval(interval time.Duration) AsyncOption {
+	return func(l *AsyncLogger) {
+		l.dropReportInterval = interval
+	}
+}
+
+// NewAsyncLogger returns an AsyncLogger writing the entries to the given logger,
+// it must be closed to handle the queued entries and stop its goroutines.
+func NewAsyncLogger(log logger.LoggerInterface, opts ...AsyncOption) *AsyncLogger {
+	l := &AsyncLogger{
+		logger:             log,
+		queueSize:          defaultAsyncQueueSize,
+		dropPolicy:         DefaultDropPolicy,
+		dropReportInterval: 10 * time.Second,
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	// an empty queue would give the drop policy a NaN fill ratio
+	if l.queueSize < 1 {
+		l.queueSize = defaultAsyncQueueSize
+	}
+	l.queue = make(chan asyncEntry, l.queueSize)
+	l.done = make(chan struct{})
+	go l.run()
+	l.stopReports = noop
+	if l.dropReportInterval > 0 {
+		l.stopReports = startTicker(context.Background(), l.dropReportInterval, func(time.Time) {
+			l.reportDropped()
+		})
+	}
+	return l
+}
+
+func (l *AsyncLogger) run() {
+	defer close(l.done)
+	for e := range l.queue {
+		if e.flushed != nil {
+			close(e.flushed)
+			continue
+		}
+		_ = l.logger.Log(e.message, e.level, e.context)
+	}
+}
+
+// Log queues the entry according to the drop policy, it returns ErrAsyncLoggerClosed once the logger is closed
+func (l *AsyncLogger) Log(message string, level logger.Level, context *logger.Context) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return ErrAsyncLoggerClosed
+	}
+	action := l.dropPolicy(level, float64(len(l.queue))/float64(cap(l.queue)))
+	if action == AsyncDrop {
+		l.drop(level)
+		return nil
+	}
+	e := asyncEntry{message: message, level: level}
+	if context != nil {
+		e.context = (&logger.Context{}).Merge(*context)
+	}
+	if action == AsyncBlock {
+		l.queue <- e
+		return nil
+	}
+	select {
+	case l.queue <- e:
+	default:
+		l.drop(level)
+	}
+	return nil
+}
+
+func (l *AsyncLogger) drop(level logger.Level) {
+	if level > logger.DebugLevel {
+		level = logger.DebugLevel
+	}
+	atomic.AddInt64(&l.dropped[level], 1)
+}
+
+// reportDropped logs the number of entries dropped by level since the last report
+func (l *AsyncLogger) reportDropped() {
+	var total int64
+	loggerContext := logger.NewContext()
+	for level := range l.dropped {
+		if count := atomic.SwapInt64(&l.dropped[level], 0); count > 0 {
+			total += count
+			loggerContext.Add("log_dropped_"+logger.Level(level).String(), count)
+		}
+	}
+	if total == 0 {
+		return
+	}
+	_ = l.logger.Warning("async logger dropped "+strconv.FormatInt(total, 10)+" entries", loggerContext.Add("log_dropped", total))
+}
+
+// Flush waits until the entries queued before the call are handled by the wrapped logger
+func (l *AsyncLogger) Flush() {
+	l.mu.RLock()
+	if l.closed {
+		l.mu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	l.queue <- asyncEntry{flushed: flushed}
+	l.mu.RUnlock()
+	<-flushed
+}
+
+// CloseNotifier is implemented by the loggers calling registered functions before they are closed, so these can still log.
+// The rate limit summaries pending when such a logger is closed are logged instead of lost,
+// a logger wrapping an AsyncLogger can implement it by delegating to it.
+type CloseNotifier interface {
+	// OnClose registers a function called before the logger is closed, the returned function unregisters it
+	OnClose(f func()) (remove func())
+}
+
+type closeHook struct {
+	f func()
+}
+
+// OnClose registers a function called by Close before the queue is closed, so it can still log its last entries
+func (l *AsyncLogger) OnClose(f func()) (remove func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return noop
+	}
+	if l.closeHooks == nil {
+		l.closeHooks = map[*closeHook]struct{}{}
+	}
+	hook := &closeHook{f: f}
+	l.closeHooks[hook] = struct{}{}
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.closeHooks, hook)
+	}
+}
+
+// Close handles the queued entries, logs the remaining dropped entries counters and stops the AsyncLogger goroutines,
+// the entries logged after it are rejected
+func (l *AsyncLogger) Close() error {
+	l.mu.Lock()
+	closeHooks := l.closeHooks
+	l.closeHooks = nil
+	l.mu.Unlock()
+	for hook := range closeHooks {
+		hook.f()
+	}
+
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.queue)
+	l.mu.Unlock()
+	<-l.done
+	l.stopReports()
+	l.reportDropped()
+	return nil
+}
+
+func (l *AsyncLogger) Debug(message string, context *logger.Context) error {
+	return l.Log(message, logger.DebugLevel, context)
+}
+
+func (l *AsyncLogger) Info(message string, context *logger.Context) error {
+	return l.Log(message, logger.InfoLevel, context)
+}
+
+func (l *AsyncLogger) Notice(message string, context *logger.Context) error {
+	return l.Log(message, logger.NoticeLevel, context)
+}
+
+func (l *AsyncLogger) Warning(message string, context *logger.Context) error {
+	return l.Log(message, logger.WarningLevel, context)
+}
+
+func (l *AsyncLogger) Error(message string, context *logger.Context) error {
+	return l.Log(message, logger.ErrorLevel, context)
+}
+
+func (l *AsyncLogger) Critical(message string, context *logger.Context) error {
+	return l.Log(message, logger.CriticalLevel, context)
+}
+
+func (l *AsyncLogger) Alert(message string, context *logger.Context) error {
+	return l.Log(message, logger.AlertLevel, context)
+}
+
+func (l *AsyncLogger) Emergency(message string, context *logger.Context) error {
+	return l.Log(message, logger.EmergencyLevel, context)
+}
@@ -0,0 +1,173 @@
+package logger_grpc_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/gol4ng/logger"
+	testing_logger "github.com/gol4ng/logger/testing"
+	// registers the use_tls test flag given to every package
+	_ "github.com/grpc-ecosystem/go-grpc-middleware/testing"
+	"github.com/stretchr/testify/assert"
+
+	logger_grpc "github.com/gol4ng/logger-grpc"
+)
+
+func TestDefaultDropPolicy(t *testing.T) {
+	tests := []struct {
+		level          logger.Level
+		fill           float64
+		expectedAction logger_grpc.AsyncAction
+	}{
+		{level: logger.EmergencyLevel, fill: 1, expectedAction: logger_grpc.AsyncBlock},
+		{level: logger.ErrorLevel, fill: 1, expectedAction: logger_grpc.AsyncBlock},
+		{level: logger.WarningLevel, fill: 1, expectedAction: logger_grpc.AsyncEnqueue},
+		{level: logger.InfoLevel, fill: 0.9, expectedAction: logger_grpc.AsyncEnqueue},
+		{level: logger.DebugLevel, fill: 0.4, expectedAction: logger_grpc.AsyncEnqueue},
+		{level: logger.DebugLevel, fill: 0.5, expectedAction: logger_grpc.AsyncDrop},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expectedAction, logger_grpc.DefaultDropPolicy(tt.level, tt.fill), "level %s, fill %v", tt.level, tt.fill)
+	}
+}
+
+func TestAsyncLogger_Flush(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+	asyncLogger := logger_grpc.NewAsyncLogger(myLogger)
+	defer asyncLogger.Close()
+
+	for _, message := range []string{"first", "second", "third"} {
+		assert.NoError(t, asyncLogger.Info(message, nil))
+	}
+	asyncLogger.Flush()
+
+	// the entries queued before the flush are handled in order when it returns
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 3)
+	for i, message := range []string{"first", "second", "third"} {
+		assert.Equal(t, message, entries[i].Message)
+	}
+}
+
+func TestAsyncLogger_WithAsyncDropPolicy(t *testing.T) {
+	received := make(chan struct{}, 10)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var handled []string
+	var fills []float64
+	// the handler holds the first entry so the next ones stay queued
+	slowLogger := logger.NewLogger(func(entry logger.Entry) error {
+		received <- struct{}{}
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, entry.Message)
+		return nil
+	})
+	asyncLogger := logger_grpc.NewAsyncLogger(slowLogger,
+		logger_grpc.WithAsyncQueueSize(2),
+		logger_grpc.WithAsyncDropReportInterval(0),
+		logger_grpc.WithAsyncDropPolicy(func(level logger.Level, fill float64) logger_grpc.AsyncAction {
+			mu.Lock()
+			defer mu.Unlock()
+			fills = append(fills, fill)
+			if level == logger.NoticeLevel {
+				return logger_grpc.AsyncDrop
+			}
+			return logger_grpc.AsyncEnqueue
+		}),
+	)
+
+	assert.NoError(t, asyncLogger.Info("held by the handler", nil))
+	<-received
+	assert.NoError(t, asyncLogger.Notice("dropped by the policy", nil))
+	assert.NoError(t, asyncLogger.Info("queued", nil))
+	assert.NoError(t, asyncLogger.Info("queued", nil))
+	assert.NoError(t, asyncLogger.Info("dropped by the full queue", nil))
+
+	close(release)
+	asyncLogger.Flush()
+
+	mu.Lock()
+	assert.Equal(t, []string{"held by the handler", "queued", "queued"}, handled)
+	for _, fill := range fills {
+		assert.True(t, fill >= 0 && fill <= 1, "the fill ratio %v is between 0 and 1", fill)
+	}
+	assert.Equal(t, 1.0, fills[len(fills)-1], "the queue is full")
+	mu.Unlock()
+
+	myLogger := &testing_logger.Logger{}
+	reportLogger := logger_grpc.NewAsyncLogger(myLogger, logger_grpc.WithAsyncDropPolicy(func(logger.Level, float64) logger_grpc.AsyncAction {
+		return logger_grpc.AsyncDrop
+	}))
+	assert.NoError(t, reportLogger.Notice("dropped", nil))
+	assert.NoError(t, reportLogger.Info("dropped", nil))
+	assert.NoError(t, reportLogger.Close())
+
+	// the dropped entries are reported on close
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "async logger dropped 2 entries", entries[0].Message)
+	assert.Equal(t, int64(1), (*entries[0].Context)["log_dropped_notice"].Value)
+	assert.Equal(t, int64(1), (*entries[0].Context)["log_dropped_info"].Value)
+	assert.NoError(t, asyncLogger.Close())
+}
+
+func TestAsyncLogger_WithInvalidQueueSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		myLogger := &testing_logger.Logger{}
+		var fill float64
+		asyncLogger := logger_grpc.NewAsyncLogger(myLogger,
+			logger_grpc.WithAsyncQueueSize(size),
+			logger_grpc.WithAsyncDropPolicy(func(level logger.Level, f float64) logger_grpc.AsyncAction {
+				fill = f
+				return logger_grpc.AsyncEnqueue
+			}),
+		)
+
+		// the default queue size is used instead
+		assert.NoError(t, asyncLogger.Info("queued", nil))
+		assert.False(t, math.IsNaN(fill), "size %d", size)
+		assert.NoError(t, asyncLogger.Close())
+		assert.Len(t, myLogger.GetEntries(), 1, "size %d", size)
+	}
+}
+
+func TestAsyncLogger_Close(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+	asyncLogger := logger_grpc.NewAsyncLogger(myLogger)
+
+	assert.NoError(t, asyncLogger.Info("queued", nil))
+	assert.NoError(t, asyncLogger.Close())
+	assert.Len(t, myLogger.GetEntries(), 1, "the queued entries are handled on close")
+
+	assert.Equal(t, logger_grpc.ErrAsyncLoggerClosed, asyncLogger.Info("rejected", nil))
+	assert.NoError(t, asyncLogger.Close(), "closing twice is allowed")
+	asyncLogger.Flush()
+}
+
+func TestAsyncLogger_OnClose(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+	asyncLogger := logger_grpc.NewAsyncLogger(myLogger)
+
+	asyncLogger.OnClose(func() {
+		assert.NoError(t, asyncLogger.Info("logged before close", nil))
+	})
+	remove := asyncLogger.OnClose(func() {
+		assert.Fail(t, "the removed functions are not called")
+	})
+	remove()
+	assert.NoError(t, asyncLogger.Close())
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "logged before close", entries[0].Message)
+
+	// the functions registered once closed are never called
+	asyncLogger.OnClose(func() {
+		assert.Fail(t, "the logger is already closed")
+	})()
+	assert.NoError(t, asyncLogger.Close())
+}
@@ -0,0 +1,74 @@
+package logger_grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/gol4ng/logger"
+	"google.golang.org/grpc/codes"
+)
+
+// CancelCause describes why a call was canceled or exceeded its deadline
+type CancelCause string
+
+const (
+	// CancelCauseNone is used when the call was neither canceled nor exceeded its deadline
+	CancelCauseNone CancelCause = ""
+	// CancelCauseClientCanceled is used when the client canceled the call (or hung up)
+	CancelCauseClientCanceled CancelCause = "client_canceled"
+	// CancelCauseClientDeadline is used when the client deadline expired
+	CancelCauseClientDeadline CancelCause = "client_deadline"
+	// CancelCauseServerTimeout is used when a deadline exceeded error is returned while the call context is still alive
+	CancelCauseServerTimeout CancelCause = "server_timeout"
+	// CancelCauseServerShutdown is used when the call was canceled while the shutdown context is done
+	CancelCauseServerShutdown CancelCause = "server_shutdown"
+	// CancelCauseCanceled is used when a canceled error is returned while the call context is still alive
+	CancelCauseCanceled CancelCause = "canceled"
+)
+
+// CancelCauseToLevel function defines the log level given the cancel cause and the level resolved from the code.
+type CancelCauseToLevel func(cause CancelCause, level logger.Level) logger.Level
+
+// DefaultCancelCauseToLevel keeps the level resolved from the code
+func DefaultCancelCauseToLevel(_ CancelCause, level logger.Level) logger.Level {
+	return level
+}
+
+// ClassifyCancelCause inspects the call context and the returned error to find out why the call was canceled
+func ClassifyCancelCause(ctx context.Context, err error, o *Options) CancelCause {
+	code := o.CodeFunc(err)
+	if code != codes.Canceled && code != codes.DeadlineExceeded {
+		return CancelCauseNone
+	}
+	if o.ShutdownContext != nil && o.ShutdownContext.Err() != nil {
+		return CancelCauseServerShutdown
+	}
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return CancelCauseClientDeadline
+	case context.Canceled:
+		// the server side deadline is shifted by the network latency, so the client hang up
+		// on its own deadline is received a bit before the call context deadline expires
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < o.ClientDeadlineGrace {
+			return CancelCauseClientDeadline
+		}
+		return CancelCauseClientCanceled
+	}
+	if code == codes.DeadlineExceeded {
+		return CancelCauseServerTimeout
+	}
+	return CancelCauseCanceled
+}
+
+// FeedCancelCause adds the cancel cause to the logger context and returns it
+func FeedCancelCause(loggerContext *logger.Context, ctx context.Context, err error, o *Options) CancelCause {
+	cause := ClassifyCancelCause(ctx, err, o)
+	if cause == CancelCauseNone {
+		return cause
+	}
+	loggerContext.Add("grpc_cancel_cause", string(cause))
+	if causeErr := contextCause(ctx); causeErr != nil && causeErr != ctx.Err() {
+		loggerContext.Add("grpc_cancel_cause_error", causeErr.Error())
+	}
+	return cause
+}
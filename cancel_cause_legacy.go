@@ -0,0 +1,11 @@
+//go:build !go1.20
+// +build !go1.20
+
+package logger_grpc
+
+import "context"
+
+// context.Cause is only available since go1.20
+func contextCause(ctx context.Context) error {
+	return nil
+}
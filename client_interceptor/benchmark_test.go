@@ -0,0 +1,78 @@
+package client_interceptor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gol4ng/logger"
+	"github.com/gol4ng/logger/middleware"
+	pb_testproto "github.com/grpc-ecosystem/go-grpc-middleware/testing/testproto"
+	"google.golang.org/grpc"
+
+	logger_grpc "github.com/gol4ng/logger-grpc"
+	"github.com/gol4ng/logger-grpc/client_interceptor"
+)
+
+func benchmarkLogger() logger.LoggerInterface {
+	return logger.NewLogger(middleware.MinLevelFilter(logger.InfoLevel)(logger.NopHandler))
+}
+
+func benchmarkContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), time.Hour)
+}
+
+func unaryInterceptorCall(interceptor grpc.UnaryClientInterceptor) func(ctx context.Context) {
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	pingResponse := &pb_testproto.PingResponse{}
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	return func(ctx context.Context) {
+		_ = interceptor(ctx, "/mwitkow.testproto.TestService/Ping", pingRequest, pingResponse, nil, invoker)
+	}
+}
+
+func streamInterceptorCall(interceptor grpc.StreamClientInterceptor) func(ctx context.Context) {
+	desc := &grpc.StreamDesc{StreamName: "PingStream", ClientStreams: true, ServerStreams: true}
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	pingResponse := &pb_testproto.PingResponse{}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &benchmarkClientStream{ctx: ctx}, nil
+	}
+	return func(ctx context.Context) {
+		stream, _ := interceptor(ctx, desc, nil, "/mwitkow.testproto.TestService/PingStream", streamer)
+		for i := 0; i < 10; i++ {
+			_ = stream.SendMsg(pingRequest)
+			_ = stream.RecvMsg(pingResponse)
+		}
+		_ = stream.CloseSend()
+		_ = stream.RecvMsg(pingResponse)
+	}
+}
+
+func runBenchmark(b *testing.B, call func(ctx context.Context)) {
+	ctx, cancel := benchmarkContext()
+	defer cancel()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		call(ctx)
+	}
+}
+
+// BenchmarkUnaryInterceptor measures a unary call logged at info level
+func BenchmarkUnaryInterceptor(b *testing.B) {
+	b.Run("default", func(b *testing.B) {
+		runBenchmark(b, unaryInterceptorCall(client_interceptor.UnaryInterceptor(benchmarkLogger())))
+	})
+	b.Run("pooled contexts", func(b *testing.B) {
+		runBenchmark(b, unaryInterceptorCall(client_interceptor.UnaryInterceptor(benchmarkLogger(), logger_grpc.WithPooledContexts(true))))
+	})
+}
+
+// BenchmarkStreamInterceptor measures a stream call of 10 request/response messages closed by the client with the debug entries disabled
+func BenchmarkStreamInterceptor(b *testing.B) {
+	runBenchmark(b, streamInterceptorCall(client_interceptor.StreamInterceptor(benchmarkLogger(), logger_grpc.WithMinLevel(logger.InfoLevel))))
+}
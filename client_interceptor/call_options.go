@@ -0,0 +1,28 @@
+package client_interceptor
+
+import (
+	"github.com/gol4ng/logger"
+	"google.golang.org/grpc"
+)
+
+// feedCallOptions adds the client connection target and the notable call options to the logger context
+func feedCallOptions(loggerContext *logger.Context, cc *grpc.ClientConn, opts []grpc.CallOption) *logger.Context {
+	if cc != nil {
+		loggerContext.Add("grpc_target", cc.Target())
+	}
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.FailFastCallOption:
+			loggerContext.Add("grpc_wait_for_ready", !o.FailFast)
+		case grpc.CompressorCallOption:
+			loggerContext.Add("grpc_compressor", o.CompressorType)
+		case grpc.MaxRecvMsgSizeCallOption:
+			loggerContext.Add("grpc_max_recv_msg_size", o.MaxRecvMsgSize)
+		case grpc.MaxSendMsgSizeCallOption:
+			loggerContext.Add("grpc_max_send_msg_size", o.MaxSendMsgSize)
+		case grpc.PerRPCCredsCallOption:
+			loggerContext.Add("grpc_per_rpc_credentials", o.Creds != nil)
+		}
+	}
+	return loggerContext
+}
@@ -0,0 +1,63 @@
+package client_interceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/gol4ng/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	logger_grpc "github.com/gol4ng/logger-grpc"
+)
+
+// WatchState logs each connectivity state transition of the given client connection.
+// It blocks until the connection is shutdown or the context is done, so it should be run in its own goroutine.
+func WatchState(ctx context.Context, cc *grpc.ClientConn, log logger.LoggerInterface, opts ...logger_grpc.Option) {
+	<-StartWatchState(ctx, cc, log, opts...)
+}
+
+// StartWatchState reads the current connectivity state of the given client connection then logs its transitions in a new goroutine,
+// the returned channel is closed once the connection is shutdown or the context is done.
+func StartWatchState(ctx context.Context, cc *grpc.ClientConn, log logger.LoggerInterface, opts ...logger_grpc.Option) <-chan struct{} {
+	o := logger_grpc.EvaluateClientOpt(opts)
+	state := cc.GetState()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchState(ctx, cc, log, o, state)
+	}()
+	return done
+}
+
+// watchState logs the transitions from the given state until the connection is shutdown or the context is done
+func watchState(ctx context.Context, cc *grpc.ClientConn, log logger.LoggerInterface, o *logger_grpc.Options, state connectivity.State) {
+	target := cc.Target()
+	stateTime := time.Now()
+	for state != connectivity.Shutdown {
+		if !cc.WaitForStateChange(ctx, state) {
+			return
+		}
+		newState := cc.GetState()
+		now := time.Now()
+		duration := now.Sub(stateTime)
+
+		loggerContext := logger.NewContext().
+			Add("grpc_kind", "client").
+			Add("grpc_target", target).
+			Add("grpc_state", newState.String()).
+			Add("grpc_previous_state", state.String()).
+			Add("grpc_state_duration", o.FormatDuration(duration))
+		_ = log.Log(o.FormatMessage(&logger_grpc.CallEvent{
+			Type:          logger_grpc.EventStateChange,
+			Kind:          "client",
+			Duration:      duration,
+			Target:        target,
+			PreviousState: state,
+			State:         newState,
+		}), o.StateLevelFunc(newState), o.ApplySchema(loggerContext))
+
+		state = newState
+		stateTime = now
+	}
+}
@@ -0,0 +1,102 @@
+package client_interceptor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gol4ng/logger"
+	testing_logger "github.com/gol4ng/logger/testing"
+	grpc_testing "github.com/grpc-ecosystem/go-grpc-middleware/testing"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	logger_grpc "github.com/gol4ng/logger-grpc"
+	"github.com/gol4ng/logger-grpc/client_interceptor"
+)
+
+func TestWatchState(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+	defer its.TearDownSuite()
+
+	clientConn, err := grpc.Dial(its.ServerAddr(), grpc.WithInsecure(), grpc.WithBlock())
+	assert.NoError(t, err)
+
+	done := client_interceptor.StartWatchState(context.Background(), clientConn, myLogger)
+
+	assert.NoError(t, clientConn.Close())
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watcher should stop when the connection is shutdown")
+	}
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, logger.NoticeLevel, entry.Level)
+	assert.Regexp(t, `grpc client connection state READY -> SHUTDOWN 127\.0\.0\.1:\d+ \[duration:.*\]`, entry.Message)
+
+	assert.Equal(t, "client", (*entry.Context)["grpc_kind"].Value)
+	assert.Equal(t, its.ServerAddr(), (*entry.Context)["grpc_target"].Value)
+	assert.Equal(t, "SHUTDOWN", (*entry.Context)["grpc_state"].Value)
+	assert.Equal(t, "READY", (*entry.Context)["grpc_previous_state"].Value)
+	assert.Contains(t, *entry.Context, "grpc_state_duration")
+}
+
+func TestWatchState_WithStateLevels(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+	defer its.TearDownSuite()
+
+	clientConn, err := grpc.Dial(its.ServerAddr(), grpc.WithInsecure(), grpc.WithBlock())
+	assert.NoError(t, err)
+
+	done := client_interceptor.StartWatchState(context.Background(), clientConn, myLogger, logger_grpc.WithStateLevels(func(state connectivity.State) logger.Level {
+		return logger.EmergencyLevel
+	}))
+
+	assert.NoError(t, clientConn.Close())
+	<-done
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, logger.EmergencyLevel, entries[0].Level)
+}
+
+func TestWatchState_ContextDone(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+	defer its.TearDownSuite()
+
+	clientConn, err := grpc.Dial(its.ServerAddr(), grpc.WithInsecure(), grpc.WithBlock())
+	assert.NoError(t, err)
+	defer clientConn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		client_interceptor.WatchState(ctx, clientConn, myLogger)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("watcher should stop when the context is done")
+	}
+	assert.Empty(t, myLogger.GetEntries())
+}
@@ -2,11 +2,11 @@ package client_interceptor
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/gol4ng/logger"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 
 	logger_grpc "github.com/gol4ng/logger-grpc"
 )
@@ -17,35 +17,43 @@ func StreamInterceptor(log logger.LoggerInterface, opts ...logger_grpc.Option) g
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (stream grpc.ClientStream, err error) {
 		startTime := time.Now()
 
+		callInfo := &logger_grpc.CallInfo{FullMethod: method, Kind: "client", CallType: "stream", StartTime: startTime}
 		currentLogger := logger.FromContext(ctx, log)
-		currentLoggerContext := logger_grpc.FeedContext(o.LoggerContextProvider(method), ctx, method, startTime).Add("grpc_kind", "client")
+		currentLoggerContext := logger_grpc.FeedCallContext(o.CallContextProvider(ctx, callInfo, nil), ctx, method, startTime, o).Set("grpc_kind", kindField)
+		feedCallOptions(currentLoggerContext, cc, opts)
+		hooks := logger_grpc.CallHooks(currentLogger, currentLoggerContext, o)
+
+		// the peer is only set once the stream is over, reading it from the stream context would disable the retries
+		callPeer := &peer.Peer{}
+		var streamWrapper *StreamWrapper
 
 		defer func() {
-			duration := time.Since(startTime)
-			currentLoggerContext.Add("grpc_duration", duration.Seconds())
+			callInfo.Duration = time.Since(startTime)
 
 			if err := recover(); err != nil {
-				currentLoggerContext.Add("grpc_panic", err)
-				_ = currentLogger.Critical(fmt.Sprintf("grpc client stream panic %s [duration:%s]", method, duration), currentLoggerContext)
+				callInfo.Panic = err
+				hooks.OnFinish(ctx, callInfo)
 				panic(err)
 			}
 
-			code := o.CodeFunc(err)
-			codeStr := code.String()
-			currentLoggerContext.Add("grpc_code", codeStr)
-			if err != nil {
-				currentLoggerContext.
-					Add("grpc_error", err).
-					Add("grpc_error_message", err.Error())
+			callInfo.Code = o.CodeFunc(err)
+			callInfo.Err = err
+			if streamWrapper == nil {
+				callInfo.Peer = callPeer.Addr
+				hooks.OnFinish(ctx, callInfo)
+				return
 			}
-
-			_ = currentLogger.Log(fmt.Sprintf("grpc client stream call %s [code:%s, duration:%s]", method, codeStr, duration), o.LevelFunc(code), currentLoggerContext)
+			// the stream wrapper calls OnFinish once the stream is over
+			hooks.OnOpen(ctx, callInfo)
+			streamWrapper.startMonitoring()
 		}()
 
-		_ = currentLogger.Debug("grpc client begin stream call "+method, currentLoggerContext)
-		stream, err = streamer(ctx, desc, cc, method, opts...)
+		hooks.OnStart(ctx, callInfo)
+		stream, err = streamer(ctx, desc, cc, method, append(opts[:len(opts):len(opts)], grpc.Peer(callPeer))...)
 		if err == nil {
-			stream = NewClientStreamWrapper(stream, o, currentLogger, *currentLoggerContext)
+			streamWrapper = newStreamWrapper(stream, ctx, o, currentLogger, *currentLoggerContext, hooks, callInfo)
+			streamWrapper.peer = callPeer
+			stream = streamWrapper
 		}
 		return stream, err
 	}
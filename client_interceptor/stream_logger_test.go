@@ -1,15 +1,23 @@
 package client_interceptor_test
 
 import (
+	"context"
+	"io"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gol4ng/logger"
 	testing_logger "github.com/gol4ng/logger/testing"
+	"github.com/golang/protobuf/proto"
 	grpc_testing "github.com/grpc-ecosystem/go-grpc-middleware/testing"
 	pb_testproto "github.com/grpc-ecosystem/go-grpc-middleware/testing/testproto"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	logger_grpc "github.com/gol4ng/logger-grpc"
 	"github.com/gol4ng/logger-grpc/client_interceptor"
@@ -53,6 +61,7 @@ func TestStreamInterceptor(t *testing.T) {
 
 	entry1 := entries[0]
 	entry1Ctx := *entry1.Context
+	assert.Equal(t, its.ServerAddr(), entry1Ctx["grpc_target"].Value)
 	assert.NotContains(t, entry1Ctx, "grpc_send_data")
 	assert.NotContains(t, entry1Ctx, "grpc_recv_data")
 	assert.Equal(t, logger.DebugLevel, entry1.Level)
@@ -60,6 +69,8 @@ func TestStreamInterceptor(t *testing.T) {
 
 	entry2 := entries[1]
 	entry2Ctx := *entry2.Context
+	// the peer is only known once the stream is over
+	assert.NotContains(t, entry2Ctx, "grpc_peer_address")
 	assert.NotContains(t, entry2Ctx, "grpc_send_data")
 	assert.NotContains(t, entry2Ctx, "grpc_recv_data")
 	assert.Equal(t, logger.InfoLevel, entry2.Level)
@@ -80,6 +91,152 @@ func TestStreamInterceptor(t *testing.T) {
 	assert.Regexp(t, `grpc client stream receive message`, entry4.Message)
 }
 
+func TestStreamInterceptor_WithMetadata(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				_ = stream.SetHeader(metadata.Pairs("x-request-id", "my_request_id"))
+				stream.SetTrailer(metadata.Pairs("x-ratelimit-remaining", "41"))
+				return handler(srv, stream)
+			}),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(myLogger, logger_grpc.WithMetadata(logger_grpc.MetadataKeys("x-request-id", "x-ratelimit-remaining")))),
+	)
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	assert.NoError(t, resp.Send(pingRequest))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+
+	header, err := resp.Header()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"my_request_id"}, header.Get("x-request-id"))
+
+	assert.NoError(t, resp.CloseSend())
+	_, err = resp.Recv()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{"41"}, resp.Trailer().Get("x-ratelimit-remaining"))
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 8)
+
+	entry5 := entries[4]
+	assert.Equal(t, logger.DebugLevel, entry5.Level)
+	assert.Equal(t, "grpc client stream receive header", entry5.Message)
+	assert.Equal(t, "my_request_id", (*entry5.Context)["grpc_header_x-request-id"].Value)
+
+	entry6 := entries[5]
+	assert.Equal(t, logger.DebugLevel, entry6.Level)
+	assert.Equal(t, "grpc client stream receive EOF", entry6.Message)
+	assert.NotContains(t, *entry6.Context, "grpc_header_x-request-id")
+	assert.NotContains(t, *entry6.Context, "grpc_trailer_x-ratelimit-remaining")
+
+	entry7 := entries[6]
+	assert.Equal(t, logger.InfoLevel, entry7.Level)
+	assert.Regexp(t, `grpc client end stream call /mwitkow\.testproto\.TestService/PingStream \[code:OK, duration:.*]`, entry7.Message)
+	assert.Equal(t, its.ServerAddr(), (*entry7.Context)["grpc_peer_address"].Value)
+	assert.Equal(t, "my_request_id", (*entry7.Context)["grpc_header_x-request-id"].Value)
+	assert.Equal(t, "41", (*entry7.Context)["grpc_trailer_x-ratelimit-remaining"].Value)
+	assert.NotContains(t, *entry7.Context, "grpc_header_content-type")
+
+	entry8 := entries[7]
+	assert.Equal(t, logger.DebugLevel, entry8.Level)
+	assert.Equal(t, "grpc client stream receive trailer", entry8.Message)
+	assert.Equal(t, "41", (*entry8.Context)["grpc_trailer_x-ratelimit-remaining"].Value)
+
+	// the headers and trailers do not depend on the debug entries
+	infoLogger := &testing_logger.Logger{}
+	c = its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(infoLogger,
+			logger_grpc.WithMinLevel(logger.InfoLevel),
+			logger_grpc.WithMetadata(logger_grpc.MetadataKeys("x-request-id", "x-ratelimit-remaining")),
+		)),
+	)
+	resp, err = c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+	assert.NoError(t, resp.CloseSend())
+	_, err = resp.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	entries = infoLogger.GetEntries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "my_request_id", (*entries[1].Context)["grpc_header_x-request-id"].Value)
+	assert.Equal(t, "41", (*entries[1].Context)["grpc_trailer_x-ratelimit-remaining"].Value)
+}
+
+func TestStreamInterceptor_WithFormats(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(myLogger, logger_grpc.WithDurationFormatter(logger_grpc.DurationMilliseconds), logger_grpc.WithMessageDuration(false))),
+	)
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "my_fake_ping_payload"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 4)
+
+	for _, e := range entries {
+		assert.IsType(t, int64(0), (*e.Context)["grpc_duration"].Value)
+	}
+	assert.Equal(t, "grpc client stream call /mwitkow.testproto.TestService/PingStream [code:OK]", entries[1].Message)
+}
+
+func TestStreamInterceptor_WithFieldSchema(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(myLogger, logger_grpc.WithFieldSchema(logger_grpc.ECSFieldSchema))),
+	)
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "my_fake_ping_payload"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 4)
+
+	for _, e := range entries {
+		eCtx := *e.Context
+		assert.Equal(t, "mwitkow.testproto.TestService", eCtx["grpc.service"].Value)
+		assert.Equal(t, "PingStream", eCtx["grpc.method"].Value)
+		assert.Equal(t, "client", eCtx["grpc.kind"].Value)
+		assert.Equal(t, its.ServerAddr(), eCtx["url.original"].Value)
+		assert.Contains(t, eCtx, "event.start")
+		assert.NotContains(t, eCtx, "grpc_service")
+		assert.NotContains(t, eCtx, "grpc_start_time")
+	}
+	assert.Contains(t, *entries[1].Context, "event.duration")
+	assert.Contains(t, *entries[2].Context, "event.duration")
+	assert.Contains(t, *entries[3].Context, "event.duration")
+}
+
 func TestStreamInterceptoor_WithContext(t *testing.T) {
 	myLogger := &testing_logger.Logger{}
 
@@ -215,3 +372,323 @@ func TestStreamInterceptoor_WithLevels(t *testing.T) {
 	assert.Equal(t, logger.DebugLevel, entry4.Level)
 	assert.Regexp(t, `grpc client stream receive message`, entry4.Message)
 }
+
+func TestStreamInterceptor_WithStreamStats(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(myLogger)),
+	)
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	assert.NoError(t, resp.Send(pingRequest))
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "ping"}))
+	assert.NoError(t, resp.CloseSend())
+	for {
+		if _, err := resp.Recv(); err != nil {
+			assert.Equal(t, io.EOF, err)
+			break
+		}
+	}
+
+	entries := myLogger.GetEntries()
+	assert.Equal(t, "grpc client stream receive EOF", entries[len(entries)-2].Message)
+	assert.NotContains(t, *entries[len(entries)-2].Context, "grpc_sent_messages")
+
+	endEntry := entries[len(entries)-1]
+	assert.Equal(t, logger.InfoLevel, endEntry.Level)
+	assert.Regexp(t, `grpc client end stream call /mwitkow\.testproto\.TestService/PingStream \[code:OK, duration:.*]`, endEntry.Message)
+
+	endCtx := *endEntry.Context
+	assert.Equal(t, int64(2), endCtx["grpc_sent_messages"].Value)
+	assert.Equal(t, int64(2), endCtx["grpc_received_messages"].Value)
+	assert.Equal(t, int64(proto.Size(pingRequest)+proto.Size(&pb_testproto.PingRequest{Value: "ping"})), endCtx["grpc_sent_bytes"].Value)
+	assert.Equal(t, int64(proto.Size(pingRequest)), endCtx["grpc_max_sent_size"].Value)
+	assert.Contains(t, endCtx, "grpc_received_bytes")
+	assert.Contains(t, endCtx, "grpc_max_received_size")
+	assert.Contains(t, endCtx, "grpc_first_message_latency")
+	assert.Contains(t, endCtx, "grpc_longest_message_gap")
+
+	// the counters do not depend on the debug entries
+	infoLogger := &testing_logger.Logger{}
+	c = its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(infoLogger, logger_grpc.WithMinLevel(logger.InfoLevel))),
+	)
+	resp, err = c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Send(pingRequest))
+	assert.NoError(t, resp.CloseSend())
+	for {
+		if _, err := resp.Recv(); err != nil {
+			break
+		}
+	}
+
+	entries = infoLogger.GetEntries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, int64(1), (*entries[1].Context)["grpc_sent_messages"].Value)
+	assert.Equal(t, int64(1), (*entries[1].Context)["grpc_received_messages"].Value)
+}
+
+func TestStreamInterceptor_WithStreamHeartbeat(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(myLogger, logger_grpc.WithStreamHeartbeat(20*time.Millisecond))),
+	)
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "my_fake_ping_payload"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	heartbeatEntries := func() (heartbeats []logger.Entry) {
+		for _, e := range myLogger.GetEntries() {
+			if strings.HasPrefix(e.Message, "grpc client stream still active /mwitkow.testproto.TestService/PingStream") {
+				heartbeats = append(heartbeats, e)
+			}
+		}
+		return heartbeats
+	}
+	assert.Eventually(t, func() bool { return len(heartbeatEntries()) >= 2 }, time.Second, time.Millisecond)
+	assert.NoError(t, resp.CloseSend())
+	_, err = resp.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	for _, e := range heartbeatEntries() {
+		eCtx := *e.Context
+		assert.Equal(t, logger.InfoLevel, e.Level)
+		assert.Contains(t, eCtx, "grpc_stream_elapsed")
+		assert.Contains(t, eCtx, "grpc_stream_idle")
+		assert.Contains(t, eCtx, "grpc_heartbeat_sent_messages")
+		assert.Contains(t, eCtx, "grpc_heartbeat_received_messages")
+	}
+
+	entries := len(myLogger.GetEntries())
+	assert.Never(t, func() bool { return len(myLogger.GetEntries()) != entries }, 50*time.Millisecond, time.Millisecond, "heartbeat must stop with the stream")
+}
+
+func TestStreamInterceptor_WithSlowCalls(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(myLogger, logger_grpc.WithSlowCalls(logger_grpc.SlowCallThresholds(time.Hour, time.Nanosecond, nil)))),
+	)
+
+	_, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 2)
+
+	entry := entries[1]
+	assert.Equal(t, logger.WarningLevel, entry.Level)
+	assert.Equal(t, true, (*entry.Context)["grpc_slow"].Value)
+}
+
+func TestStreamInterceptor_WithPayloadLimits(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(myLogger, logger_grpc.WithPayloadLimits(logger_grpc.PayloadLimits{MaxSize: 10}))),
+	)
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	assert.NoError(t, resp.Send(pingRequest))
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 3)
+
+	sendCtx := *entries[2].Context
+	assert.Equal(t, `{"value":"`+logger_grpc.TruncationMarker, sendCtx["grpc_send_data"].Value)
+	assert.Equal(t, true, sendCtx["grpc_send_data_truncated"].Value)
+	assert.Equal(t, int64(proto.Size(pingRequest)), sendCtx["grpc_send_data_size"].Value)
+}
+
+func TestStreamInterceptor_WithHooks(t *testing.T) {
+	var mu sync.Mutex
+	var opened []logger_grpc.CallInfo
+	var finished []logger_grpc.CallInfo
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+	defer its.TearDownSuite()
+
+	c := its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(&testing_logger.Logger{}, logger_grpc.WithHooks(logger_grpc.HookFuncs{
+			Open: func(ctx context.Context, info *logger_grpc.CallInfo) {
+				mu.Lock()
+				defer mu.Unlock()
+				opened = append(opened, *info)
+			},
+			Finish: func(ctx context.Context, info *logger_grpc.CallInfo) {
+				mu.Lock()
+				defer mu.Unlock()
+				finished = append(finished, *info)
+			},
+		}))),
+	)
+	calls := func() ([]logger_grpc.CallInfo, []logger_grpc.CallInfo) {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]logger_grpc.CallInfo(nil), opened...), append([]logger_grpc.CallInfo(nil), finished...)
+	}
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "my_fake_ping_payload"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+
+	open, finish := calls()
+	assert.Len(t, open, 1)
+	assert.Nil(t, open[0].Peer)
+	assert.Empty(t, finish, "the stream is not over yet")
+
+	assert.NoError(t, resp.CloseSend())
+	_, err = resp.Recv()
+	assert.Equal(t, io.EOF, err)
+	_, err = resp.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	_, finish = calls()
+	assert.Len(t, finish, 1)
+	assert.Equal(t, codes.OK, finish[0].Code)
+	assert.Equal(t, its.ServerAddr(), finish[0].Peer.String())
+	assert.Equal(t, int64(1), finish[0].SentMessages)
+	assert.Equal(t, int64(1), finish[0].ReceivedMessages)
+	assert.True(t, finish[0].Duration >= open[0].Duration)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err = c.PingStream(ctx)
+	assert.NoError(t, err)
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		_, finish := calls()
+		return len(finish) == 2
+	}, time.Second, time.Millisecond)
+	_, finish = calls()
+	assert.Equal(t, codes.Canceled, finish[1].Code)
+}
+
+func TestStreamInterceptor_WithCallLevels(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+	defer its.TearDownSuite()
+
+	c := its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(myLogger, logger_grpc.WithCallLevels(func(info *logger_grpc.CallInfo) logger.Level {
+			if info.CallType == "stream" && info.FullMethod == "/mwitkow.testproto.TestService/PingStream" && info.Code == codes.Canceled && info.Err != nil {
+				return logger.AlertLevel
+			}
+			return logger.InfoLevel
+		}))),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resp, err := c.PingStream(ctx)
+	assert.NoError(t, err)
+	cancel()
+	_, err = resp.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+
+	// the receive error level is given by the call levels with the error code
+	var recvErrorEntries []logger.Entry
+	for _, e := range myLogger.GetEntries() {
+		if e.Message == "grpc client stream receive error" {
+			recvErrorEntries = append(recvErrorEntries, e)
+		}
+	}
+	assert.Len(t, recvErrorEntries, 1)
+	assert.Equal(t, logger.AlertLevel, recvErrorEntries[0].Level)
+	assert.Equal(t, "Canceled", (*recvErrorEntries[0].Context)["grpc_code"].Value)
+}
+
+func TestStreamInterceptor_WithLogFielder(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &benchmarkClientStream{ctx: ctx}, nil
+	}
+	interceptor := client_interceptor.StreamInterceptor(myLogger)
+	stream, err := interceptor(context.Background(), &grpc.StreamDesc{StreamName: "PingStream", ClientStreams: true, ServerStreams: true}, nil, "/mwitkow.testproto.TestService/PingStream", streamer)
+	assert.NoError(t, err)
+
+	assert.NoError(t, stream.SendMsg(&logFieldsRequest{id: "first_request"}))
+	assert.NoError(t, stream.SendMsg(&logFieldsRequest{id: "second_request"}))
+	assert.NoError(t, stream.CloseSend())
+	assert.Equal(t, io.EOF, stream.RecvMsg(&pb_testproto.PingResponse{}))
+
+	// the fields of the first stream message are logged on the end entry
+	entries := myLogger.GetEntries()
+	endEntry := entries[len(entries)-1]
+	assert.Regexp(t, `grpc client end stream call /mwitkow\.testproto\.TestService/PingStream \[code:OK, duration:.*]`, endEntry.Message)
+	assert.Equal(t, "first_request", (*endEntry.Context)["request_id"].Value)
+}
+
+func TestStreamInterceptor_WithDeadlineWarning(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+	defer its.TearDownSuite()
+
+	c := its.NewClient(
+		grpc.WithStreamInterceptor(client_interceptor.StreamInterceptor(myLogger, logger_grpc.WithDeadlineWarning(0.5))),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp, err := c.PingStream(ctx)
+	assert.NoError(t, err)
+	// no message is sent, the receive waits for the deadline
+	_, err = resp.Recv()
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	// the end entry is logged by the deadline monitoring or by the receive error, so its position varies
+	assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 4 }, time.Second, time.Millisecond)
+	entries := myLogger.GetEntries()
+
+	openEntry := entries[1]
+	assert.Regexp(t, `grpc client stream call /mwitkow\.testproto\.TestService/PingStream \[code:OK, duration:.*]`, openEntry.Message)
+	assert.Contains(t, *openEntry.Context, "grpc_deadline_budget")
+	assert.NotContains(t, *openEntry.Context, "grpc_deadline_budget_used")
+
+	endEntry := entries[3]
+	if strings.HasPrefix(entries[2].Message, "grpc client end") {
+		endEntry = entries[2]
+	}
+	assert.Regexp(t, `grpc client end stream call /mwitkow\.testproto\.TestService/PingStream \[code:DeadlineExceeded, duration:.*]`, endEntry.Message)
+	assert.Equal(t, logger.WarningLevel, endEntry.Level)
+	assert.InDelta(t, 1, (*endEntry.Context)["grpc_deadline_budget_used"].Value, 0.2)
+	assert.Equal(t, true, (*endEntry.Context)["grpc_deadline_near"].Value)
+}
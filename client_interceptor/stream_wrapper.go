@@ -1,11 +1,16 @@
 package client_interceptor
 
 import (
+	"context"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/gol4ng/logger"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
 	logger_grpc "github.com/gol4ng/logger-grpc"
 )
@@ -15,48 +20,204 @@ type StreamWrapper struct {
 	options       *logger_grpc.Options
 	logger        logger.LoggerInterface
 	loggerContext logger.Context
+	hooks         logger_grpc.Hooks
+	mu            sync.Mutex
+	info          *logger_grpc.CallInfo
+	// context is the call context, the stream is over when it is done
+	context context.Context
+	// finished is closed once the hooks OnFinish is called
+	finished   chan struct{}
+	finishOnce sync.Once
+	// peer is filled by the grpc.Peer call option when the stream is over
+	peer *peer.Peer
+	// snapshotFunc is the snapshot method value, it is bound once instead of on each send
+	snapshotFunc      func() logger_grpc.CallInfo
+	stopHeartbeat     func()
+	stopIdleDetection func()
 }
 
 func (s *StreamWrapper) getLoggerContext() *logger.Context {
-	return (&logger.Context{}).Merge(s.loggerContext)
+	return s.options.AcquireContext().Merge(s.loggerContext)
+}
+
+func (c *StreamWrapper) event(eventType logger_grpc.EventType, duration time.Duration) *logger_grpc.CallEvent {
+	return &logger_grpc.CallEvent{Type: eventType, Kind: "client", CallType: "stream", FullMethod: c.info.FullMethod, Duration: duration}
+}
+
+func (c *StreamWrapper) log(event *logger_grpc.CallEvent, level logger.Level, ctx *logger.Context) {
+	_ = c.logger.Log(c.options.FormatMessage(event), level, c.options.ApplySchema(ctx))
+	c.options.ReleaseContext(ctx)
+}
+
+func (c *StreamWrapper) logError(event *logger_grpc.CallEvent, ctx *logger.Context, err error) {
+	code, level := c.options.ErrorLevel(c.snapshot(), err)
+	if !c.options.Enabled(level) {
+		c.options.ReleaseContext(ctx)
+		return
+	}
+	event.Code = code
+	event.Err = err
+	c.log(event, level, logger_grpc.FeedStatusDetails(ctx.Add("grpc_error", err).Add("grpc_code", code.String()), err, c.options))
+}
+
+func (c *StreamWrapper) Header() (metadata.MD, error) {
+	md, err := c.ClientStream.Header()
+	if err != nil {
+		c.logError(c.event(logger_grpc.EventReceiveHeaderError, 0), logger_grpc.FeedMetadata(c.getLoggerContext(), "grpc_header_", md, c.options), err)
+		return md, err
+	}
+	if c.options.Enabled(logger.DebugLevel) {
+		c.log(c.event(logger_grpc.EventReceiveHeader, 0), logger.DebugLevel, logger_grpc.FeedMetadata(c.getLoggerContext(), "grpc_header_", md, c.options))
+	}
+	return md, err
+}
+
+func (c *StreamWrapper) Trailer() metadata.MD {
+	md := c.ClientStream.Trailer()
+	if !c.options.Enabled(logger.DebugLevel) {
+		return md
+	}
+	c.log(c.event(logger_grpc.EventReceiveTrailer, 0), logger.DebugLevel, logger_grpc.FeedMetadata(c.getLoggerContext(), "grpc_trailer_", md, c.options))
+	return md
+}
+
+// snapshot returns a copy of the current call info
+func (c *StreamWrapper) snapshot() logger_grpc.CallInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return *c.info
+}
+
+// startMonitoring logs the heartbeat and idle entries until the end of the stream and finishes the stream when the call context is done
+func (c *StreamWrapper) startMonitoring() {
+	c.stopHeartbeat = logger_grpc.StartHeartbeat(c.context, c.logger, &c.loggerContext, c.options, c.snapshot)
+	c.stopIdleDetection = logger_grpc.StartIdleDetection(c.context, c.logger, &c.loggerContext, c.options, c.snapshot)
+	if done := c.context.Done(); done != nil {
+		go func() {
+			select {
+			case <-done:
+				c.finish(status.FromContextError(c.context.Err()).Err())
+			case <-c.finished:
+			}
+		}()
+	}
+}
+
+// finish calls the hooks OnFinish once, at the end of stream, on error or when the call context is done
+func (c *StreamWrapper) finish(err error) {
+	c.finishOnce.Do(func() {
+		close(c.finished)
+		c.stopHeartbeat()
+		c.stopIdleDetection()
+		c.mu.Lock()
+		c.info.Duration = time.Since(c.info.StartTime)
+		c.info.Code = c.options.CodeFunc(err)
+		c.info.Err = err
+		info := *c.info
+		c.mu.Unlock()
+		c.hooks.OnFinish(c.context, &info)
+	})
+}
+
+// onMessage records the message in the call info and calls the hooks with a snapshot of the call info,
+// the snapshot is only taken when the message is dispatched to the hooks
+func (c *StreamWrapper) onMessage(message logger_grpc.MessageInfo) {
+	c.mu.Lock()
+	if message.Type == logger_grpc.EventSend && c.info.SentMessages == 0 {
+		c.info.RequestFields = c.options.ExtractRequestFields(message.Message)
+	}
+	c.info.RecordMessage(&message)
+	// a send error other than io.EOF ends the stream, the io.EOF status is received by RecvMsg
+	endOfStream := message.Type == logger_grpc.EventReceiveEOF || message.Type == logger_grpc.EventReceiveError ||
+		(message.Type == logger_grpc.EventSendError && message.Err != io.EOF)
+	if endOfStream {
+		c.recordEndOfStream()
+	}
+	dispatch := c.options.DispatchMessage(message.Type)
+	var info logger_grpc.CallInfo
+	if dispatch {
+		info = *c.info
+	}
+	c.mu.Unlock()
+	if dispatch {
+		c.dispatchMessage(info, message)
+	}
+	if endOfStream {
+		c.finishWith(message)
+	}
+}
+
+// finishWith finishes the stream ended by the given message
+func (c *StreamWrapper) finishWith(message logger_grpc.MessageInfo) {
+	if message.Type == logger_grpc.EventReceiveEOF {
+		c.finish(nil)
+		return
+	}
+	c.finish(message.Err)
+}
+
+// dispatchMessage calls the hooks, the call info and message copies are only allocated when they are dispatched
+func (c *StreamWrapper) dispatchMessage(info logger_grpc.CallInfo, message logger_grpc.MessageInfo) {
+	c.hooks.OnMessage(c.context, &info, &message)
+}
+
+// recordEndOfStream adds the received headers, trailers and the peer to the call info once the stream is over,
+// grpc has then finished the stream so the peer call option is filled
+func (c *StreamWrapper) recordEndOfStream() {
+	if md, err := c.ClientStream.Header(); err == nil {
+		c.info.Header = md
+	}
+	c.info.Trailer = c.ClientStream.Trailer()
+	c.info.Peer = c.peer.Addr
 }
 
 func (c *StreamWrapper) SendMsg(m interface{}) error {
 	startTime := time.Now()
+	sendDone := logger_grpc.WatchSendStall(c.logger, &c.loggerContext, c.options, c.snapshotFunc)
 	err := c.ClientStream.SendMsg(m)
-	ctx := c.getLoggerContext().Add("grpc_send_data", m).Add("grpc_duration", time.Since(startTime).Seconds())
+	sendDone()
+	eventType := logger_grpc.EventSend
 	if err != nil {
-		code := c.options.CodeFunc(err)
-		_ = c.logger.Log("grpc client stream send error", c.options.LevelFunc(code), ctx.Add("grpc_error", err).Add("grpc_code", code.String()))
-		return err
+		eventType = logger_grpc.EventSendError
 	}
-	_ = c.logger.Debug("grpc client stream send message", ctx)
+	c.onMessage(logger_grpc.NewMessageInfo(eventType, m, time.Since(startTime), err))
 	return err
 }
 
 func (c *StreamWrapper) RecvMsg(m interface{}) error {
 	startTime := time.Now()
 	err := c.ClientStream.RecvMsg(m)
-	ctx := c.getLoggerContext().Add("grpc_duration", time.Since(startTime).Seconds())
-	if err == io.EOF {
-		_ = c.logger.Debug("grpc client stream receive EOF", ctx)
-		return err
-	}
-	ctx.Add("grpc_recv_data", m)
-	if err != nil {
-		code := c.options.CodeFunc(err)
-		_ = c.logger.Log("grpc client stream receive error", c.options.LevelFunc(code), ctx.Add("grpc_error", err).Add("grpc_code", code.String()))
-		return err
+	eventType := logger_grpc.EventReceive
+	switch {
+	case err == io.EOF:
+		eventType = logger_grpc.EventReceiveEOF
+	case err != nil:
+		eventType = logger_grpc.EventReceiveError
 	}
-	_ = c.logger.Debug("grpc client stream receive message", ctx)
+	c.onMessage(logger_grpc.NewMessageInfo(eventType, m, time.Since(startTime), err))
 	return err
 }
 
 func NewClientStreamWrapper(stream grpc.ClientStream, options *logger_grpc.Options, l logger.LoggerInterface, loggerContext logger.Context) *StreamWrapper {
-	return &StreamWrapper{
-		ClientStream:  stream,
-		options:       options,
-		logger:        l,
-		loggerContext: loggerContext,
+	info := &logger_grpc.CallInfo{Kind: "client", CallType: "stream", StartTime: time.Now()}
+	return newStreamWrapper(stream, context.Background(), options, l, loggerContext, logger_grpc.CallHooks(l, &loggerContext, options), info)
+}
+
+// newStreamWrapper returns the wrapper of a stream sharing the call hooks and info of the interceptor
+func newStreamWrapper(stream grpc.ClientStream, ctx context.Context, options *logger_grpc.Options, l logger.LoggerInterface, loggerContext logger.Context, hooks logger_grpc.Hooks, info *logger_grpc.CallInfo) *StreamWrapper {
+	wrapper := &StreamWrapper{
+		ClientStream:      stream,
+		options:           options,
+		logger:            l,
+		loggerContext:     loggerContext,
+		hooks:             hooks,
+		info:              info,
+		context:           ctx,
+		finished:          make(chan struct{}),
+		peer:              &peer.Peer{},
+		stopHeartbeat:     func() {},
+		stopIdleDetection: func() {},
 	}
+	wrapper.snapshotFunc = wrapper.snapshot
+	return wrapper
 }
@@ -0,0 +1,77 @@
+package client_interceptor_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gol4ng/logger"
+	"github.com/gol4ng/logger/middleware"
+	pb_testproto "github.com/grpc-ecosystem/go-grpc-middleware/testing/testproto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	logger_grpc "github.com/gol4ng/logger-grpc"
+	"github.com/gol4ng/logger-grpc/client_interceptor"
+)
+
+type benchmarkClientStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	closed bool
+}
+
+func (s *benchmarkClientStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *benchmarkClientStream) SendMsg(interface{}) error {
+	return nil
+}
+
+func (s *benchmarkClientStream) CloseSend() error {
+	s.closed = true
+	return nil
+}
+
+func (s *benchmarkClientStream) RecvMsg(interface{}) error {
+	if s.closed {
+		return io.EOF
+	}
+	return nil
+}
+
+func (s *benchmarkClientStream) Header() (metadata.MD, error) {
+	return nil, nil
+}
+
+func (s *benchmarkClientStream) Trailer() metadata.MD {
+	return nil
+}
+
+func benchmarkStreamWrapper(b *testing.B, opts ...logger_grpc.Option) {
+	myLogger := logger.NewLogger(middleware.MinLevelFilter(logger.InfoLevel)(logger.NopHandler))
+	ctx := context.Background()
+	o := logger_grpc.EvaluateClientOpt(opts)
+	loggerContext := logger_grpc.FeedCallContext(nil, ctx, "/mwitkow.testproto.TestService/PingStream", time.Now(), o)
+	stream := client_interceptor.NewClientStreamWrapper(&benchmarkClientStream{ctx: ctx}, o, myLogger, *loggerContext)
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	pingResponse := &pb_testproto.PingResponse{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = stream.SendMsg(pingRequest)
+		_ = stream.RecvMsg(pingResponse)
+	}
+}
+
+func BenchmarkStreamWrapper_DebugDisabled(b *testing.B) {
+	b.Run("without level gate", func(b *testing.B) {
+		benchmarkStreamWrapper(b)
+	})
+	b.Run("with min level", func(b *testing.B) {
+		benchmarkStreamWrapper(b, logger_grpc.WithMinLevel(logger.InfoLevel))
+	})
+}
@@ -2,46 +2,61 @@ package client_interceptor
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/gol4ng/logger"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
 	logger_grpc "github.com/gol4ng/logger-grpc"
 )
 
+// kindField is the precomputed grpc_kind field of the client calls
+var kindField = logger.String("client")
+
 // UnaryInterceptor returns a new unary client interceptor that optionally logs the execution of external gRPC calls.
 func UnaryInterceptor(log logger.LoggerInterface, opts ...logger_grpc.Option) grpc.UnaryClientInterceptor {
 	o := logger_grpc.EvaluateClientOpt(opts)
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) (err error) {
 		startTime := time.Now()
 
+		callInfo := &logger_grpc.CallInfo{FullMethod: method, Kind: "client", CallType: "unary", StartTime: startTime, Request: req}
 		currentLogger := logger.FromContext(ctx, log)
-		currentLoggerContext := logger_grpc.FeedContext(o.LoggerContextProvider(method), ctx, method, startTime).Add("grpc_kind", "client")
+		currentLoggerContext := o.CallContextProvider(ctx, callInfo, req)
+		if currentLoggerContext == nil {
+			currentLoggerContext = o.AcquireContext()
+			defer o.ReleaseContext(currentLoggerContext)
+		}
+		logger_grpc.FeedCallContext(currentLoggerContext, ctx, method, startTime, o).Set("grpc_kind", kindField)
+		feedCallOptions(currentLoggerContext, cc, opts)
+		hooks := logger_grpc.CallHooks(currentLogger, currentLoggerContext, o)
+		callPeer := &peer.Peer{}
+		header := metadata.MD{}
+		trailer := metadata.MD{}
 
 		defer func() {
-			duration := time.Since(startTime)
-			currentLoggerContext.Add("grpc_duration", duration.Seconds())
+			callInfo.Duration = time.Since(startTime)
+			callInfo.Peer = callPeer.Addr
+			callInfo.Header = header
+			callInfo.Trailer = trailer
 
 			if err := recover(); err != nil {
-				currentLoggerContext.Add("grpc_panic", err)
-				_ = currentLogger.Critical(fmt.Sprintf("grpc client unary panic %s [duration:%s]", method, duration), currentLoggerContext)
+				callInfo.Panic = err
+				hooks.OnFinish(ctx, callInfo)
 				panic(err)
 			}
 
-			code := o.CodeFunc(err)
-			codeStr := code.String()
-			currentLoggerContext.Add("grpc_code", codeStr)
-			if err != nil {
-				currentLoggerContext.
-					Add("grpc_error", err).
-					Add("grpc_error_message", err.Error())
+			callInfo.Code = o.CodeFunc(err)
+			callInfo.Err = err
+			if err == nil {
+				callInfo.Response = reply
 			}
-
-			_ = currentLogger.Log(fmt.Sprintf("grpc client unary call %s [code:%s, duration:%s]", method, codeStr, duration), o.LevelFunc(code), currentLoggerContext)
+			hooks.OnFinish(ctx, callInfo)
 		}()
 
-		return invoker(ctx, method, req, reply, cc, opts...)
+		callInfo.RequestFields = o.ExtractRequestFields(req)
+		hooks.OnStart(ctx, callInfo)
+		return invoker(ctx, method, req, reply, cc, append(opts[:len(opts):len(opts)], grpc.Peer(callPeer), grpc.Header(&header), grpc.Trailer(&trailer))...)
 	}
 }
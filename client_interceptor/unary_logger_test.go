@@ -1,15 +1,23 @@
 package client_interceptor_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/gol4ng/logger"
 	testing_logger "github.com/gol4ng/logger/testing"
+	"github.com/golang/protobuf/ptypes"
 	grpc_testing "github.com/grpc-ecosystem/go-grpc-middleware/testing"
 	pb_testproto "github.com/grpc-ecosystem/go-grpc-middleware/testing/testproto"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
 	logger_grpc "github.com/gol4ng/logger-grpc"
 	"github.com/gol4ng/logger-grpc/client_interceptor"
@@ -45,6 +53,149 @@ func TestUnaryInterceptor(t *testing.T) {
 	assert.Contains(t, *entry.Context, "grpc_start_time")
 	assert.Contains(t, *entry.Context, "grpc_request_deadline")
 	assert.Contains(t, *entry.Context, "grpc_duration")
+	assert.Equal(t, its.ServerAddr(), (*entry.Context)["grpc_target"].Value)
+	assert.Equal(t, its.ServerAddr(), (*entry.Context)["grpc_peer_address"].Value)
+	assert.InDelta(t, 2, (*entry.Context)["grpc_deadline_budget"].Value, 0.1)
+	assert.Contains(t, *entry.Context, "grpc_deadline_budget_used")
+}
+
+func TestUnaryInterceptor_WithCallOptions(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithUnaryInterceptor(client_interceptor.UnaryInterceptor(myLogger)),
+	)
+	userPeer := &peer.Peer{}
+	resp, err := c.Ping(
+		its.SimpleCtx(),
+		&pb_testproto.PingRequest{Value: "my_fake_ping_payload"},
+		grpc.WaitForReady(true),
+		grpc.UseCompressor(gzip.Name),
+		grpc.MaxCallRecvMsgSize(1024),
+		grpc.MaxCallSendMsgSize(2048),
+		grpc.Peer(userPeer),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "my_fake_ping_payload", resp.Value)
+	assert.Equal(t, its.ServerAddr(), userPeer.Addr.String())
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, its.ServerAddr(), (*entry.Context)["grpc_target"].Value)
+	assert.Equal(t, its.ServerAddr(), (*entry.Context)["grpc_peer_address"].Value)
+	assert.Equal(t, true, (*entry.Context)["grpc_wait_for_ready"].Value)
+	assert.Equal(t, "gzip", (*entry.Context)["grpc_compressor"].Value)
+	assert.Equal(t, int64(1024), (*entry.Context)["grpc_max_recv_msg_size"].Value)
+	assert.Equal(t, int64(2048), (*entry.Context)["grpc_max_send_msg_size"].Value)
+	assert.NotContains(t, *entry.Context, "grpc_per_rpc_credentials")
+}
+
+func TestUnaryInterceptor_WithMetadata(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", "my_request_id", "x-not-logged", "value"))
+				_ = grpc.SetTrailer(ctx, metadata.Pairs("x-ratelimit-remaining", "41"))
+				return handler(ctx, req)
+			}),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithUnaryInterceptor(client_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithMetadata(logger_grpc.MetadataKeys("X-Request-Id", "x-ratelimit-remaining")))),
+	)
+	userHeader := metadata.MD{}
+	resp, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}, grpc.Header(&userHeader))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "my_fake_ping_payload", resp.Value)
+	assert.Equal(t, []string{"my_request_id"}, userHeader.Get("x-request-id"))
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, "my_request_id", (*entry.Context)["grpc_header_x-request-id"].Value)
+	assert.Equal(t, "41", (*entry.Context)["grpc_trailer_x-ratelimit-remaining"].Value)
+	assert.NotContains(t, *entry.Context, "grpc_header_x-not-logged")
+	assert.NotContains(t, *entry.Context, "grpc_header_content-type")
+}
+
+func TestUnaryInterceptor_WithStatusDetails(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				st, _ := status.New(codes.ResourceExhausted, "too many pings").WithDetails(
+					&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "client:my_client", Description: "ping quota exceeded"}}},
+					&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(1500 * time.Millisecond)},
+				)
+				return nil, st.Err()
+			}),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithUnaryInterceptor(client_interceptor.UnaryInterceptor(myLogger)),
+	)
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, logger.WarningLevel, entry.Level)
+	assert.Equal(t, "ResourceExhausted", (*entry.Context)["grpc_code"].Value)
+	assert.Equal(t, []map[string]string{{"subject": "client:my_client", "description": "ping quota exceeded"}}, (*entry.Context)["grpc_error_quota_violations"].Value)
+	assert.Equal(t, 1.5, (*entry.Context)["grpc_error_retry_delay"].Value)
+	assert.NotContains(t, *entry.Context, "grpc_error_details")
+}
+
+func TestUnaryInterceptor_WithCancelCause(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if req.(*pb_testproto.PingRequest).Value == "slow" {
+					<-ctx.Done()
+				}
+				return nil, status.Error(codes.DeadlineExceeded, "database timeout")
+			}),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithUnaryInterceptor(client_interceptor.UnaryInterceptor(myLogger)),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := c.Ping(ctx, &pb_testproto.PingRequest{Value: "slow"})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	_, err = c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "fast"})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "client_deadline", (*entries[0].Context)["grpc_cancel_cause"].Value)
+	assert.Equal(t, "server_timeout", (*entries[1].Context)["grpc_cancel_cause"].Value)
 }
 
 func TestUnaryInterceptor_WithContext(t *testing.T) {
@@ -116,3 +267,56 @@ func TestUnaryInterceptor_WithLevels(t *testing.T) {
 	assert.Contains(t, *entry.Context, "grpc_request_deadline")
 	assert.Contains(t, *entry.Context, "grpc_duration")
 }
+
+func TestUnaryInterceptor_WithHooks(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+	var finished *logger_grpc.CallInfo
+
+	its := &grpc_testing.InterceptorTestSuite{}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient(
+		grpc.WithUnaryInterceptor(client_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithHooks(logger_grpc.HookFuncs{
+			Finish: func(ctx context.Context, info *logger_grpc.CallInfo) {
+				finished = info
+			},
+		}))),
+	)
+	_, err := c.PingError(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload", ErrorCodeReturned: uint32(codes.NotFound)})
+	assert.Error(t, err)
+
+	assert.Len(t, myLogger.GetEntries(), 1)
+	assert.NotNil(t, finished)
+	assert.Equal(t, "/mwitkow.testproto.TestService/PingError", finished.FullMethod)
+	assert.Equal(t, "client", finished.Kind)
+	assert.Equal(t, "unary", finished.CallType)
+	assert.Equal(t, codes.NotFound, finished.Code)
+	assert.Equal(t, err, finished.Err)
+	assert.Equal(t, its.ServerAddr(), finished.Peer.String())
+}
+
+// logFieldsRequest is a request message providing its own log fields
+type logFieldsRequest struct {
+	id string
+}
+
+func (r *logFieldsRequest) LogFields() map[string]interface{} {
+	return map[string]interface{}{"request_id": r.id}
+}
+
+func TestUnaryInterceptor_WithLogFielder(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	interceptor := client_interceptor.UnaryInterceptor(myLogger)
+	err := interceptor(context.Background(), "/mwitkow.testproto.TestService/Ping", &logFieldsRequest{id: "my_request"}, &pb_testproto.PingResponse{}, nil, invoker)
+	assert.NoError(t, err)
+
+	// the LogFieldsExtractor is a default field extractor
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "my_request", (*entries[0].Context)["request_id"].Value)
+}
@@ -0,0 +1,117 @@
+package logger_grpc
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldExtractor function returns the fields to log from a request message
+type FieldExtractor func(message interface{}) map[string]interface{}
+
+// LogFielder is implemented by the messages providing their own log fields
+type LogFielder interface {
+	LogFields() map[string]interface{}
+}
+
+// LogFieldsExtractor is a FieldExtractor returning the fields of the messages implementing LogFielder
+func LogFieldsExtractor(message interface{}) map[string]interface{} {
+	if m, ok := message.(LogFielder); ok {
+		return m.LogFields()
+	}
+	return nil
+}
+
+// ProtoFields returns a FieldExtractor reading the proto message fields with protoreflect,
+// the given map associates a dot separated field path (eg: "order.id") to the logged field name (eg: "order_id").
+// The unset fields are not logged.
+func ProtoFields(paths map[string]string) FieldExtractor {
+	fieldPaths := make(map[string][]protoreflect.Name, len(paths))
+	for path, fieldName := range paths {
+		var names []protoreflect.Name
+		for _, name := range strings.Split(path, ".") {
+			names = append(names, protoreflect.Name(name))
+		}
+		fieldPaths[fieldName] = names
+	}
+	return func(message interface{}) map[string]interface{} {
+		m, ok := message.(proto.Message)
+		if !ok {
+			return nil
+		}
+		msg := proto.MessageReflect(m)
+		fields := make(map[string]interface{}, len(fieldPaths))
+		for fieldName, path := range fieldPaths {
+			if value, ok := protoFieldValue(msg, path); ok {
+				fields[fieldName] = value
+			}
+		}
+		return fields
+	}
+}
+
+func protoFieldValue(msg protoreflect.Message, path []protoreflect.Name) (interface{}, bool) {
+	for i, name := range path {
+		fd := msg.Descriptor().Fields().ByName(name)
+		if fd == nil || !msg.Has(fd) {
+			return nil, false
+		}
+		value := msg.Get(fd)
+		if i == len(path)-1 {
+			return protoValue(fd, value), true
+		}
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			return nil, false
+		}
+		msg = value.Message()
+	}
+	return nil, false
+}
+
+func protoValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	if fd.IsList() {
+		list := value.List()
+		values := make([]interface{}, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			values = append(values, protoScalarValue(fd, list.Get(i)))
+		}
+		return values
+	}
+	if fd.IsMap() {
+		values := map[string]interface{}{}
+		value.Map().Range(func(key protoreflect.MapKey, v protoreflect.Value) bool {
+			values[key.String()] = protoScalarValue(fd.MapValue(), v)
+			return true
+		})
+		return values
+	}
+	return protoScalarValue(fd, value)
+}
+
+func protoScalarValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if enumValue := fd.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return int32(value.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return value.Message().Interface()
+	}
+	return value.Interface()
+}
+
+// ExtractRequestFields returns the fields of the request message extracted by the field extractors
+func (o *Options) ExtractRequestFields(message interface{}) map[string]interface{} {
+	var fields map[string]interface{}
+	for _, extract := range o.FieldExtractors {
+		for name, value := range extract(message) {
+			if fields == nil {
+				fields = map[string]interface{}{}
+			}
+			fields[name] = value
+		}
+	}
+	return fields
+}
@@ -0,0 +1,145 @@
+package logger_grpc
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gol4ng/logger"
+	"google.golang.org/grpc/codes"
+)
+
+// DurationFormatter function defines the representation of the durations in the logger context
+type DurationFormatter func(d time.Duration) interface{}
+
+// DurationSeconds represents the durations as float seconds
+func DurationSeconds(d time.Duration) interface{} {
+	return d.Seconds()
+}
+
+// DurationMilliseconds represents the durations as integer milliseconds
+func DurationMilliseconds(d time.Duration) interface{} {
+	return int64(d / time.Millisecond)
+}
+
+// DurationNanoseconds represents the durations as integer nanoseconds
+func DurationNanoseconds(d time.Duration) interface{} {
+	return d.Nanoseconds()
+}
+
+// DurationString represents the durations as human readable string (eg: 1.5ms)
+func DurationString(d time.Duration) interface{} {
+	return d.String()
+}
+
+// FormatTime formats the time with the configured layout and location
+func (o *Options) FormatTime(t time.Time) string {
+	if o.TimeLocation != nil {
+		t = t.In(o.TimeLocation)
+	}
+	return t.Format(o.TimeLayout)
+}
+
+// FormatDuration formats the duration with the configured duration formatter
+func (o *Options) FormatDuration(d time.Duration) interface{} {
+	return o.DurationFormatter(d)
+}
+
+// joinMessage appends the bracketed "name:value" details to the message with a single allocation
+func joinMessage(message string, details []string) string {
+	if len(details) == 0 {
+		return message
+	}
+	size := len(message) + len(" []") + len(details)/2 + (len(details)/2-1)*len(", ")
+	for _, detail := range details {
+		size += len(detail)
+	}
+	var b strings.Builder
+	b.Grow(size)
+	b.WriteString(message)
+	b.WriteString(" [")
+	for i := 0; i+1 < len(details); i += 2 {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(details[i])
+		b.WriteByte(':')
+		b.WriteString(details[i+1])
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// cachedTime is a formatted time, valid for every time of the same second and location
+type cachedTime struct {
+	unix     int64
+	location *time.Location
+	field    logger.Field
+}
+
+// timeCache keeps the last formatted time, it is only used with the layouts without fractional seconds
+type timeCache struct {
+	last atomic.Value
+}
+
+// newTimeCache returns a timeCache for the layout or nil when the layout has fractional seconds
+func newTimeCache(layout string) *timeCache {
+	for _, fraction := range []string{".0", ".9", ",0", ",9"} {
+		if strings.Contains(layout, fraction) {
+			return nil
+		}
+	}
+	return &timeCache{}
+}
+
+// timeField returns the formatted time field, it is only formatted once per second when the layout allows it
+func (o *Options) timeField(cache *timeCache, t time.Time) logger.Field {
+	if o.TimeLocation != nil {
+		t = t.In(o.TimeLocation)
+	}
+	if cache == nil {
+		return logger.String(t.Format(o.TimeLayout))
+	}
+	unix := t.Unix()
+	if last, ok := cache.last.Load().(*cachedTime); ok && last.unix == unix && last.location == t.Location() {
+		return last.field
+	}
+	last := &cachedTime{unix: unix, location: t.Location(), field: logger.String(t.Format(o.TimeLayout))}
+	cache.last.Store(last)
+	return last.field
+}
+
+// codeFields are the precomputed grpc_code fields of the standard codes
+var codeFields = func() []logger.Field {
+	fields := make([]logger.Field, codes.Unauthenticated+1)
+	for code := range fields {
+		fields[code] = logger.String(codes.Code(code).String())
+	}
+	return fields
+}()
+
+// codeField returns the grpc_code field of the code
+func codeField(code codes.Code) logger.Field {
+	if int(code) < len(codeFields) {
+		return codeFields[code]
+	}
+	return logger.String(code.String())
+}
+
+// valueField returns the field of a formatted value, the common types keep the value as is to avoid boxing it twice
+func valueField(value interface{}) logger.Field {
+	switch value.(type) {
+	case float64:
+		return logger.Field{Type: logger.Float64Type, Value: value}
+	case int64:
+		return logger.Field{Type: logger.Int64Type, Value: value}
+	case string:
+		return logger.Field{Type: logger.StringType, Value: value}
+	}
+	return logger.Any(value)
+}
+
+// durationField returns the field of the duration formatted with the configured duration formatter
+func (o *Options) durationField(d time.Duration) logger.Field {
+	return valueField(o.DurationFormatter(d))
+}
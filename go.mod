@@ -1,10 +1,22 @@
 module github.com/gol4ng/logger-grpc
 
-go 1.13
+go 1.19
 
 require (
 	github.com/gol4ng/logger v0.3.3
+	github.com/golang/protobuf v1.5.3
 	github.com/grpc-ecosystem/go-grpc-middleware v1.1.0
-	github.com/stretchr/testify v1.4.0
-	google.golang.org/grpc v1.26.0
+	github.com/stretchr/testify v1.8.2
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1
+	google.golang.org/grpc v1.54.0
+	google.golang.org/protobuf v1.30.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.11.0 // indirect
+	golang.org/x/sys v0.9.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
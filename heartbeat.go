@@ -0,0 +1,67 @@
+package logger_grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gol4ng/logger"
+)
+
+// StartHeartbeat logs a "stream still active" entry every heartbeat interval until the returned stop function is called or the context is done.
+// The snapshot function must return a copy of the current stream call info.
+func StartHeartbeat(ctx context.Context, log logger.LoggerInterface, loggerContext *logger.Context, o *Options, snapshot func() CallInfo) (stop func()) {
+	if o.HeartbeatInterval <= 0 {
+		return noop
+	}
+	hook := NewLoggingHook(log, loggerContext, o)
+	previous := snapshot()
+	return startTicker(ctx, o.HeartbeatInterval, func(now time.Time) {
+		current := snapshot()
+		hook.heartbeat(&current, &previous, now)
+		previous = current
+	})
+}
+
+// startTicker calls the tick function every interval until the returned stop function is called or the context is done,
+// the stop function waits for the running tick to return.
+func startTicker(ctx context.Context, interval time.Duration, tick func(now time.Time)) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				tick(now)
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// heartbeat logs the stream activity since the previous heartbeat
+func (h *LoggingHook) heartbeat(info *CallInfo, previous *CallInfo, now time.Time) {
+	elapsed := now.Sub(info.StartTime)
+	ctx := h.entryContext(info).
+		Add("grpc_stream_elapsed", h.options.FormatDuration(elapsed)).
+		Add("grpc_stream_idle", h.options.FormatDuration(now.Sub(info.idleSince(info.LastMessageTime)))).
+		Add("grpc_heartbeat_sent_messages", info.SentMessages-previous.SentMessages).
+		Add("grpc_heartbeat_received_messages", info.ReceivedMessages-previous.ReceivedMessages)
+
+	event := h.event(EventHeartbeat, info)
+	event.Duration = elapsed
+	h.logEntry(event, logger.InfoLevel, ctx)
+}
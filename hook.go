@@ -0,0 +1,187 @@
+package logger_grpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/gol4ng/logger"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// CallInfo contains the typed information of a gRPC call
+type CallInfo struct {
+	FullMethod string
+	// Kind is "server" or "client"
+	Kind string
+	// CallType is "unary" or "stream"
+	CallType string
+	Peer     net.Addr
+	// RequestFields are extracted from the unary request or the first stream request message
+	RequestFields map[string]interface{}
+	// Request and Response are the unary call messages, the response is only set for the successful calls
+	Request   interface{}
+	Response  interface{}
+	Header    metadata.MD
+	Trailer   metadata.MD
+	StartTime time.Time
+	// Duration, Code, Err and Panic are set when the call is finished
+	Duration time.Duration
+	Code     codes.Code
+	Err      error
+	Panic    interface{}
+
+	SentMessages        int64
+	ReceivedMessages    int64
+	SentBytes           int64
+	ReceivedBytes       int64
+	MaxSentSize         int
+	MaxReceivedSize     int
+	FirstMessageLatency time.Duration
+	LongestMessageGap   time.Duration
+	LastMessageTime     time.Time
+	LastSentTime        time.Time
+	LastReceivedTime    time.Time
+}
+
+// RecordMessage updates the message counters of the call
+func (i *CallInfo) RecordMessage(m *MessageInfo) {
+	switch m.Type {
+	case EventSend:
+		i.SentMessages++
+		i.SentBytes += int64(m.Size)
+		if m.Size > i.MaxSentSize {
+			i.MaxSentSize = m.Size
+		}
+		i.LastSentTime = m.Time
+	case EventReceive:
+		i.ReceivedMessages++
+		i.ReceivedBytes += int64(m.Size)
+		if m.Size > i.MaxReceivedSize {
+			i.MaxReceivedSize = m.Size
+		}
+		i.LastReceivedTime = m.Time
+	default:
+		return
+	}
+	if i.LastMessageTime.IsZero() {
+		i.FirstMessageLatency = m.Time.Sub(i.StartTime)
+	} else if gap := m.Time.Sub(i.LastMessageTime); gap > i.LongestMessageGap {
+		i.LongestMessageGap = gap
+	}
+	i.LastMessageTime = m.Time
+}
+
+// idleSince returns the time of the last activity, the last message time or the start time when no message was exchanged
+func (i *CallInfo) idleSince(last time.Time) time.Time {
+	if last.After(i.StartTime) {
+		return last
+	}
+	return i.StartTime
+}
+
+// MessageInfo contains the typed information of a stream message
+type MessageInfo struct {
+	// Type is one of EventSend, EventSendError, EventReceive, EventReceiveEOF or EventReceiveError
+	Type     EventType
+	Message  interface{}
+	Size     int
+	Duration time.Duration
+	Err      error
+	// Time is the time the message was sent or received
+	Time time.Time
+}
+
+// NewMessageInfo returns the MessageInfo of the given message, the size is only known for proto messages
+func NewMessageInfo(eventType EventType, m interface{}, duration time.Duration, err error) MessageInfo {
+	info := MessageInfo{Type: eventType, Message: m, Duration: duration, Err: err, Time: time.Now()}
+	if eventType == EventSend || eventType == EventReceive {
+		if pm, ok := m.(proto.Message); ok {
+			info.Size = proto.Size(pm)
+		}
+	}
+	return info
+}
+
+// Hook is called by the interceptors at the call start, on each stream message and at the call completion,
+// OnFinish is called once the client streams are over (end of stream, error or call context done)
+type Hook interface {
+	OnStart(ctx context.Context, info *CallInfo)
+	OnMessage(ctx context.Context, info *CallInfo, message *MessageInfo)
+	OnFinish(ctx context.Context, info *CallInfo)
+}
+
+// OpenHook is implemented by the hooks notified when a client stream is open, before its completion
+type OpenHook interface {
+	OnOpen(ctx context.Context, info *CallInfo)
+}
+
+// HookFuncs is a Hook calling the non nil functions
+type HookFuncs struct {
+	Start   func(ctx context.Context, info *CallInfo)
+	Open    func(ctx context.Context, info *CallInfo)
+	Message func(ctx context.Context, info *CallInfo, message *MessageInfo)
+	Finish  func(ctx context.Context, info *CallInfo)
+}
+
+func (h HookFuncs) OnStart(ctx context.Context, info *CallInfo) {
+	if h.Start != nil {
+		h.Start(ctx, info)
+	}
+}
+
+func (h HookFuncs) OnOpen(ctx context.Context, info *CallInfo) {
+	if h.Open != nil {
+		h.Open(ctx, info)
+	}
+}
+
+func (h HookFuncs) OnMessage(ctx context.Context, info *CallInfo, message *MessageInfo) {
+	if h.Message != nil {
+		h.Message(ctx, info, message)
+	}
+}
+
+func (h HookFuncs) OnFinish(ctx context.Context, info *CallInfo) {
+	if h.Finish != nil {
+		h.Finish(ctx, info)
+	}
+}
+
+// Hooks calls each hook in order
+type Hooks []Hook
+
+func (h Hooks) OnStart(ctx context.Context, info *CallInfo) {
+	for _, hook := range h {
+		hook.OnStart(ctx, info)
+	}
+}
+
+// OnOpen calls the hooks implementing OpenHook
+func (h Hooks) OnOpen(ctx context.Context, info *CallInfo) {
+	for _, hook := range h {
+		if openHook, ok := hook.(OpenHook); ok {
+			openHook.OnOpen(ctx, info)
+		}
+	}
+}
+
+func (h Hooks) OnMessage(ctx context.Context, info *CallInfo, message *MessageInfo) {
+	for _, hook := range h {
+		hook.OnMessage(ctx, info, message)
+	}
+}
+
+func (h Hooks) OnFinish(ctx context.Context, info *CallInfo) {
+	for _, hook := range h {
+		hook.OnFinish(ctx, info)
+	}
+}
+
+// CallHooks returns the hooks of a call, the logging hook first then the configured hooks
+func CallHooks(log logger.LoggerInterface, loggerContext *logger.Context, o *Options) Hooks {
+	hooks := make(Hooks, 0, len(o.Hooks)+1)
+	return append(append(hooks, NewLoggingHook(log, loggerContext, o)), o.Hooks...)
+}
@@ -0,0 +1,191 @@
+package logger_grpc
+
+import (
+	"context"
+
+	"github.com/gol4ng/logger"
+	"google.golang.org/grpc/codes"
+)
+
+// LoggingHook is the Hook writing the call logs
+type LoggingHook struct {
+	options       *Options
+	logger        logger.LoggerInterface
+	loggerContext *logger.Context
+	// open is set once the client stream is open, its completion is then logged apart from the open entry
+	open bool
+}
+
+// NewLoggingHook returns the Hook logging a call with the given logger context
+func NewLoggingHook(log logger.LoggerInterface, loggerContext *logger.Context, o *Options) *LoggingHook {
+	return &LoggingHook{
+		options:       o,
+		logger:        log,
+		loggerContext: loggerContext,
+	}
+}
+
+// feedCallInfo adds the request fields and the resolved peer address of the client calls to the logger context
+func (h *LoggingHook) feedCallInfo(loggerContext *logger.Context, info *CallInfo) *logger.Context {
+	if info.Kind == "client" && info.Peer != nil {
+		loggerContext.Add("grpc_peer_address", info.Peer.String())
+	}
+	for name, value := range info.RequestFields {
+		loggerContext.Add(name, value)
+	}
+	return loggerContext
+}
+
+// feedStreamStats adds the stream message counters to the logger context
+func (h *LoggingHook) feedStreamStats(loggerContext *logger.Context, info *CallInfo) *logger.Context {
+	loggerContext.
+		Set("grpc_sent_messages", logger.Int64(info.SentMessages)).
+		Set("grpc_received_messages", logger.Int64(info.ReceivedMessages)).
+		Set("grpc_sent_bytes", logger.Int64(info.SentBytes)).
+		Set("grpc_received_bytes", logger.Int64(info.ReceivedBytes)).
+		Set("grpc_max_sent_size", logger.Int64(int64(info.MaxSentSize))).
+		Set("grpc_max_received_size", logger.Int64(int64(info.MaxReceivedSize)))
+	if info.SentMessages+info.ReceivedMessages > 0 {
+		loggerContext.
+			Set("grpc_first_message_latency", h.options.durationField(info.FirstMessageLatency)).
+			Set("grpc_longest_message_gap", h.options.durationField(info.LongestMessageGap))
+	}
+	return loggerContext
+}
+
+func (h *LoggingHook) event(eventType EventType, info *CallInfo) *CallEvent {
+	return &CallEvent{Type: eventType, Kind: info.Kind, CallType: info.CallType, FullMethod: info.FullMethod}
+}
+
+func (h *LoggingHook) log(event *CallEvent, level logger.Level, ctx *logger.Context) {
+	if !h.options.Enabled(level) {
+		return
+	}
+	_ = h.logger.Log(h.options.FormatMessage(event), level, h.options.ApplySchema(ctx))
+}
+
+// entryContext returns a copy of the call logger context for a single entry, it must be logged with logEntry
+func (h *LoggingHook) entryContext(info *CallInfo) *logger.Context {
+	return h.feedCallInfo(h.options.AcquireContext().Merge(*h.loggerContext), info)
+}
+
+// logEntry logs the entry then releases its logger context
+func (h *LoggingHook) logEntry(event *CallEvent, level logger.Level, ctx *logger.Context) {
+	h.log(event, level, ctx)
+	h.options.ReleaseContext(ctx)
+}
+
+// OnStart logs the stream calls begin
+func (h *LoggingHook) OnStart(_ context.Context, info *CallInfo) {
+	if info.CallType == "stream" && h.options.Enabled(logger.DebugLevel) {
+		h.log(h.event(EventBegin, info), logger.DebugLevel, h.loggerContext)
+	}
+}
+
+// OnMessage logs the stream message, the logger context and the payload are only built when the entry level is enabled
+func (h *LoggingHook) OnMessage(_ context.Context, info *CallInfo, message *MessageInfo) {
+	level := logger.DebugLevel
+	var code codes.Code
+	isError := message.Type == EventSendError || message.Type == EventReceiveError
+	if isError {
+		code, level = h.options.ErrorLevel(*info, message.Err)
+	}
+	if !h.options.Enabled(level) || (isError && !h.options.AllowEntry(h.logger, info, code, level)) {
+		return
+	}
+
+	event := h.event(message.Type, info)
+	event.Duration = message.Duration
+	ctx := h.entryContext(info).Set("grpc_duration", h.options.durationField(message.Duration))
+	switch message.Type {
+	case EventSend, EventSendError:
+		h.options.PayloadEncoder(ctx, "grpc_send_data", message.Message)
+	case EventReceive, EventReceiveError:
+		h.options.PayloadEncoder(ctx, "grpc_recv_data", message.Message)
+	}
+	if isError {
+		event.Code = code
+		event.Err = message.Err
+		h.logEntry(event, level, FeedStatusDetails(ctx.Add("grpc_error", message.Err).Set("grpc_code", codeField(code)), message.Err, h.options))
+		return
+	}
+	h.logEntry(event, level, ctx)
+}
+
+// OnOpen logs the client stream opening, the stream completion is logged by OnFinish
+func (h *LoggingHook) OnOpen(_ context.Context, info *CallInfo) {
+	h.open = true
+	loggerContext := h.feedCallInfo(h.loggerContext, info).
+		Set("grpc_duration", h.options.durationField(info.Duration)).
+		Set("grpc_code", codeField(info.Code))
+
+	event := h.event(EventCall, info)
+	event.Duration = info.Duration
+	event.Code = info.Code
+	// the deadline budget consumed by the stream is only known at its completion
+	level := h.options.CallLevelFunc(info)
+	if FeedSlowCall(loggerContext, info.FullMethod, info.CallType, info.Duration, h.options) {
+		level = EscalateLevel(level, logger.WarningLevel)
+	}
+	if !h.options.Enabled(level) || !h.options.AllowEntry(h.logger, info, info.Code, level) {
+		return
+	}
+	h.log(event, level, loggerContext)
+}
+
+// OnFinish logs the call completion, the completion of the open client streams is logged with a copy of the logger context
+// as the stream entries may still be using it
+func (h *LoggingHook) OnFinish(ctx context.Context, info *CallInfo) {
+	event := h.event(EventCall, info)
+	loggerContext := h.loggerContext
+	if h.open {
+		event.Type = EventEnd
+		loggerContext = h.options.AcquireContext().Merge(*h.loggerContext)
+		defer h.options.ReleaseContext(loggerContext)
+	}
+	h.feedCallInfo(loggerContext, info).Set("grpc_duration", h.options.durationField(info.Duration))
+	FeedMetadata(loggerContext, "grpc_header_", info.Header, h.options)
+	FeedMetadata(loggerContext, "grpc_trailer_", info.Trailer, h.options)
+	if info.CallType == "stream" && (info.Kind == "server" || h.open) {
+		h.feedStreamStats(loggerContext, info)
+	}
+	if info.CallType == "unary" && h.options.UnaryPayloads {
+		if info.Request != nil {
+			h.options.PayloadEncoder(loggerContext, "grpc_request_data", info.Request)
+		}
+		if info.Response != nil {
+			h.options.PayloadEncoder(loggerContext, "grpc_response_data", info.Response)
+		}
+	}
+
+	event.Duration = info.Duration
+	if info.Panic != nil {
+		event.Type = EventPanic
+		event.Panic = info.Panic
+		loggerContext.Add("grpc_panic", info.Panic)
+		h.log(event, logger.CriticalLevel, loggerContext)
+		return
+	}
+
+	event.Code = info.Code
+	event.Err = info.Err
+	loggerContext.Set("grpc_code", codeField(info.Code))
+	if info.Err != nil {
+		loggerContext.
+			Add("grpc_error", info.Err).
+			Add("grpc_error_message", info.Err.Error())
+		FeedStatusDetails(loggerContext, info.Err, h.options)
+	}
+	cause := FeedCancelCause(loggerContext, ctx, info.Err, h.options)
+	level := h.options.CancelCauseLevelFunc(cause, h.options.CallLevelFunc(info))
+	if FeedDeadlineBudget(loggerContext, ctx, info.StartTime, info.Duration, h.options) {
+		level = EscalateLevel(level, logger.WarningLevel)
+	}
+	if FeedSlowCall(loggerContext, info.FullMethod, info.CallType, info.Duration, h.options) {
+		level = EscalateLevel(level, logger.WarningLevel)
+	}
+	if !h.options.Enabled(level) || !h.options.AllowEntry(h.logger, info, info.Code, level) {
+		return
+	}
+	h.log(event, level, loggerContext)
+}
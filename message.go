@@ -0,0 +1,119 @@
+package logger_grpc
+
+import (
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+)
+
+// EventType defines the event a log message is written for
+type EventType string
+
+const (
+	EventCall               EventType = "call"
+	EventEnd                EventType = "end"
+	EventPanic              EventType = "panic"
+	EventBegin              EventType = "begin"
+	EventSend               EventType = "send"
+	EventSendError          EventType = "send_error"
+	EventReceive            EventType = "receive"
+	EventReceiveEOF         EventType = "receive_eof"
+	EventReceiveError       EventType = "receive_error"
+	EventSendHeader         EventType = "send_header"
+	EventSendHeaderError    EventType = "send_header_error"
+	EventReceiveHeader      EventType = "receive_header"
+	EventReceiveHeaderError EventType = "receive_header_error"
+	EventReceiveTrailer     EventType = "receive_trailer"
+	EventConnBegin          EventType = "conn_begin"
+	EventConnEnd            EventType = "conn_end"
+	EventStateChange        EventType = "state_change"
+	EventHeartbeat          EventType = "heartbeat"
+	EventStreamIdle         EventType = "stream_idle"
+	EventSendStall          EventType = "send_stall"
+	EventSuppressed         EventType = "suppressed"
+)
+
+// CallEvent contains the information available to build a log message
+type CallEvent struct {
+	Type EventType
+	// Kind is "server" or "client"
+	Kind string
+	// CallType is "unary" or "stream"
+	CallType   string
+	FullMethod string
+	Code       codes.Code
+	Duration   time.Duration
+	Err        error
+	// Panic is the recovered value for EventPanic
+	Panic interface{}
+	// RemoteAddr and RPCCount are set for the connection events
+	RemoteAddr string
+	RPCCount   int64
+	// Target, PreviousState and State are set for the EventStateChange
+	Target        string
+	PreviousState connectivity.State
+	State         connectivity.State
+	// Suppressed is the number of entries suppressed during the Duration for the EventSuppressed
+	Suppressed int64
+}
+
+// MessageFormatter function defines the log message written for a call event
+type MessageFormatter func(event *CallEvent) string
+
+var streamEventMessages = map[EventType]string{
+	EventSend:               "send message",
+	EventSendError:          "send error",
+	EventReceive:            "receive message",
+	EventReceiveEOF:         "receive EOF",
+	EventReceiveError:       "receive error",
+	EventSendHeader:         "send header",
+	EventSendHeaderError:    "send header error",
+	EventReceiveHeader:      "receive header",
+	EventReceiveHeaderError: "receive header error",
+	EventReceiveTrailer:     "receive trailer",
+}
+
+// DefaultMessageFormatter returns the default MessageFormatter, messageDuration defines if the duration is embedded in the messages
+func DefaultMessageFormatter(messageDuration bool) MessageFormatter {
+	// details returns the message followed by the bracketed details, the details are "name:value" pairs with the duration last
+	details := func(message string, duration time.Duration, details ...string) string {
+		if messageDuration {
+			details = append(details, "duration", duration.String())
+		}
+		return joinMessage(message, details)
+	}
+	return func(e *CallEvent) string {
+		switch e.Type {
+		case EventCall:
+			return details("grpc "+e.Kind+" "+e.CallType+" call "+e.FullMethod, e.Duration, "code", e.Code.String())
+		case EventEnd:
+			return details("grpc "+e.Kind+" end "+e.CallType+" call "+e.FullMethod, e.Duration, "code", e.Code.String())
+		case EventPanic:
+			return details("grpc "+e.Kind+" "+e.CallType+" panic "+e.FullMethod, e.Duration)
+		case EventBegin:
+			return "grpc " + e.Kind + " begin " + e.CallType + " call " + e.FullMethod
+		case EventHeartbeat:
+			return details("grpc "+e.Kind+" "+e.CallType+" still active "+e.FullMethod, e.Duration)
+		case EventStreamIdle:
+			return details("grpc "+e.Kind+" "+e.CallType+" idle "+e.FullMethod, e.Duration)
+		case EventSendStall:
+			return details("grpc "+e.Kind+" "+e.CallType+" send stalled "+e.FullMethod, e.Duration)
+		case EventSuppressed:
+			return "suppressed " + strconv.FormatInt(e.Suppressed, 10) + " " + e.Code.String() + " entries for " + e.FullMethod + " in last " + e.Duration.String()
+		case EventConnBegin:
+			return "grpc " + e.Kind + " connection begin " + e.RemoteAddr
+		case EventConnEnd:
+			return details("grpc "+e.Kind+" connection end "+e.RemoteAddr, e.Duration, "rpc_count", strconv.FormatInt(e.RPCCount, 10))
+		case EventStateChange:
+			return details("grpc "+e.Kind+" connection state "+e.PreviousState.String()+" -> "+e.State.String()+" "+e.Target, e.Duration)
+		}
+		return "grpc " + e.Kind + " " + e.CallType + " " + streamEventMessages[e.Type]
+	}
+}
+
+// FormatMessage returns the log message of the call event
+func (o *Options) FormatMessage(event *CallEvent) string {
+	return o.MessageFormatter(event)
+}
@@ -0,0 +1,50 @@
+package logger_grpc
+
+import (
+	"path"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gol4ng/logger"
+)
+
+// maxCachedMethods bounds the method cache, the servers receive arbitrary method names from the unknown service handler
+const maxCachedMethods = 1024
+
+// methodFields contains the precomputed logger fields of a full method name
+type methodFields struct {
+	service logger.Field
+	method  logger.Field
+}
+
+func newMethodFields(fullMethod string) *methodFields {
+	return &methodFields{
+		service: logger.String(path.Dir(fullMethod)[1:]),
+		method:  logger.String(path.Base(fullMethod)),
+	}
+}
+
+// methodCache stores the methodFields by full method name
+type methodCache struct {
+	fields sync.Map
+	size   int64
+}
+
+// get returns the methodFields of the full method name, they are only computed once per method
+func (c *methodCache) get(fullMethod string) *methodFields {
+	if c == nil {
+		return newMethodFields(fullMethod)
+	}
+	if f, ok := c.fields.Load(fullMethod); ok {
+		return f.(*methodFields)
+	}
+	f := newMethodFields(fullMethod)
+	if atomic.LoadInt64(&c.size) >= maxCachedMethods {
+		return f
+	}
+	actual, loaded := c.fields.LoadOrStore(fullMethod, f)
+	if !loaded {
+		atomic.AddInt64(&c.size, 1)
+	}
+	return actual.(*methodFields)
+}
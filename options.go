@@ -7,26 +7,142 @@ package logger_grpc
 
 import (
 	"context"
-	"path"
+	"strings"
 	"time"
 
 	"github.com/gol4ng/logger"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
 	"google.golang.org/grpc/status"
 )
 
 type Options struct {
 	LoggerContextProvider LoggerContextProvider
+	CallContextProvider   CallContextProvider
 	LevelFunc             CodeToLevel
+	CallLevelFunc         CallToLevel
 	CodeFunc              func(error) codes.Code
+	ConnDecider           ConnDecider
+	StateLevelFunc        StateToLevel
+	MetadataMapper        MetadataMapper
+	MetadataRedactor      MetadataRedactor
+	StatusDetailRenderers []StatusDetailRenderer
+	ShutdownContext       context.Context
+	CancelCauseLevelFunc  CancelCauseToLevel
+	ClientDeadlineGrace   time.Duration
+	DeadlineWarningRatio  float64
+	TimeLayout            string
+	TimeLocation          *time.Location
+	DurationFormatter     DurationFormatter
+	MessageDuration       bool
+	FieldSchema           FieldSchema
+	MessageFormatter      MessageFormatter
+	Hooks                 []Hook
+	HeartbeatInterval     time.Duration
+	StreamIdleTimeout     time.Duration
+	SendStallThreshold    time.Duration
+	SlowCallThreshold     SlowCallThreshold
+	FieldExtractors       []FieldExtractor
+	PayloadEncoder        PayloadEncoder
+	UnaryPayloads         bool
+	LevelEnabled          LevelEnabled
+	PooledContexts        bool
+	// RateLimitFunc is nil when the entries are not rate limited
+	RateLimitFunc            RateLimitFunc
+	RateLimitSummaryInterval time.Duration
+
+	methods     *methodCache
+	rateLimiter *rateLimiter
+	startTimes  *timeCache
+	deadlines   *timeCache
 }
 
 // LoggerContextProvider function defines the default logger context values
 type LoggerContextProvider func(fullMethodName string) *logger.Context
 
+// CallContextProvider function defines the default logger context values given the call context (incoming or outgoing metadata, auth claims...),
+// the call info and the request message for the unary calls (nil for the streams)
+type CallContextProvider func(ctx context.Context, info *CallInfo, req interface{}) *logger.Context
+
+// LevelEnabled function defines if the entries of the given level are handled by the logger,
+// the fields and payloads of the disabled entries are not built
+type LevelEnabled func(level logger.Level) bool
+
+// MinLevel returns a LevelEnabled that only handle the entries as severe as the given level or more
+func MinLevel(min logger.Level) LevelEnabled {
+	return func(level logger.Level) bool {
+		return level <= min
+	}
+}
+
 // CodeToLevel function defines the mapping between gRPC return codes and interceptor log level.
 type CodeToLevel func(code codes.Code) logger.Level
 
+// CallToLevel function defines the interceptor log level given the call summary, the code and error are the ones of the call or of the failed stream operation.
+type CallToLevel func(info *CallInfo) logger.Level
+
+// ConnDecider function defines if the connection lifecycle should be logged (eg: to skip health probe connections)
+type ConnDecider func(info *stats.ConnTagInfo) bool
+
+// StateToLevel function defines the mapping between client connection connectivity states and log level.
+type StateToLevel func(state connectivity.State) logger.Level
+
+// MetadataMapper function defines if a metadata key should be logged and the field name to use.
+type MetadataMapper func(key string) (fieldName string, ok bool)
+
+// MetadataKeys returns a MetadataMapper that only log the given metadata keys
+func MetadataKeys(keys ...string) MetadataMapper {
+	allowed := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		allowed[strings.ToLower(key)] = struct{}{}
+	}
+	return func(key string) (string, bool) {
+		_, ok := allowed[key]
+		return key, ok
+	}
+}
+
+// AllMetadata is a MetadataMapper that log every metadata keys
+func AllMetadata(key string) (string, bool) {
+	return key, true
+}
+
+// SlowCallThreshold function defines the duration over which a call is slow given its full method and call type ("unary" or "stream"), 0 disables the detection.
+type SlowCallThreshold func(fullMethod string, callType string) time.Duration
+
+// SlowCallThresholds returns a SlowCallThreshold using the method threshold when defined (keyed by full method eg: "/my.Service/Method"),
+// or the unary or stream threshold according to the call type
+func SlowCallThresholds(unary time.Duration, stream time.Duration, methods map[string]time.Duration) SlowCallThreshold {
+	return func(fullMethod string, callType string) time.Duration {
+		if threshold, ok := methods[fullMethod]; ok {
+			return threshold
+		}
+		if callType == "stream" {
+			return stream
+		}
+		return unary
+	}
+}
+
+// MetadataRedactor function defines the metadata values written in the logs
+type MetadataRedactor func(key string, values []string) []string
+
+// RedactMetadataKeys returns a MetadataRedactor that hide the values of the given metadata keys
+func RedactMetadataKeys(keys ...string) MetadataRedactor {
+	redacted := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		redacted[strings.ToLower(key)] = struct{}{}
+	}
+	return func(key string, values []string) []string {
+		if _, ok := redacted[key]; ok {
+			return []string{"[REDACTED]"}
+		}
+		return values
+	}
+}
+
 func DefaultCodeToLevel(code codes.Code) logger.Level {
 	switch code {
 	case codes.OK, codes.Canceled, codes.NotFound, codes.AlreadyExists:
@@ -40,6 +156,16 @@ func DefaultCodeToLevel(code codes.Code) logger.Level {
 	return logger.ErrorLevel
 }
 
+func DefaultStateToLevel(state connectivity.State) logger.Level {
+	switch state {
+	case connectivity.Idle, connectivity.Connecting, connectivity.Ready:
+		return logger.InfoLevel
+	case connectivity.Shutdown:
+		return logger.NoticeLevel
+	}
+	return logger.WarningLevel
+}
+
 func newDefaultOptions() *Options {
 	return &Options{
 		LoggerContextProvider: func(fullMethodName string) *logger.Context {
@@ -47,23 +173,72 @@ func newDefaultOptions() *Options {
 		},
 		LevelFunc: DefaultCodeToLevel,
 		CodeFunc:  status.Code,
+		ConnDecider: func(info *stats.ConnTagInfo) bool {
+			return true
+		},
+		StateLevelFunc: DefaultStateToLevel,
+		MetadataMapper: func(key string) (string, bool) {
+			return "", false
+		},
+		MetadataRedactor: func(key string, values []string) []string {
+			return values
+		},
+		StatusDetailRenderers: []StatusDetailRenderer{DefaultStatusDetailRenderer},
+		CancelCauseLevelFunc:  DefaultCancelCauseToLevel,
+		ClientDeadlineGrace:   50 * time.Millisecond,
+		TimeLayout:            time.RFC3339,
+		DurationFormatter:     DurationSeconds,
+		MessageDuration:       true,
+		SlowCallThreshold: func(fullMethod string, callType string) time.Duration {
+			return 0
+		},
+		FieldExtractors: []FieldExtractor{LogFieldsExtractor},
+		PayloadEncoder:  RawPayload,
+		LevelEnabled: func(level logger.Level) bool {
+			return true
+		},
+		RateLimitSummaryInterval: 10 * time.Second,
 	}
 }
 
-func EvaluateServerOpt(opts []Option) *Options {
+func evaluateOpt(opts []Option) *Options {
 	optCopy := newDefaultOptions()
 	for _, o := range opts {
 		o(optCopy)
 	}
+	optCopy.methods = &methodCache{}
+	optCopy.startTimes = newTimeCache(optCopy.TimeLayout)
+	optCopy.deadlines = newTimeCache(optCopy.TimeLayout)
+	if optCopy.RateLimitFunc != nil {
+		if optCopy.RateLimitSummaryInterval <= 0 {
+			optCopy.RateLimitSummaryInterval = newDefaultOptions().RateLimitSummaryInterval
+		}
+		optCopy.rateLimiter = newRateLimiter(optCopy)
+	}
+	if optCopy.MessageFormatter == nil {
+		optCopy.MessageFormatter = DefaultMessageFormatter(optCopy.MessageDuration)
+	}
+	if optCopy.CallContextProvider == nil {
+		loggerContextProvider := optCopy.LoggerContextProvider
+		optCopy.CallContextProvider = func(_ context.Context, info *CallInfo, _ interface{}) *logger.Context {
+			return loggerContextProvider(info.FullMethod)
+		}
+	}
+	if optCopy.CallLevelFunc == nil {
+		levelFunc := optCopy.LevelFunc
+		optCopy.CallLevelFunc = func(info *CallInfo) logger.Level {
+			return levelFunc(info.Code)
+		}
+	}
 	return optCopy
 }
 
+func EvaluateServerOpt(opts []Option) *Options {
+	return evaluateOpt(opts)
+}
+
 func EvaluateClientOpt(opts []Option) *Options {
-	optCopy := newDefaultOptions()
-	for _, o := range opts {
-		o(optCopy)
-	}
-	return optCopy
+	return evaluateOpt(opts)
 }
 
 type Option func(*Options)
@@ -75,6 +250,13 @@ func WithLoggerContext(f LoggerContextProvider) Option {
 	}
 }
 
+// WithCallContext customizes the function for providing the default logger context from the call context and request, it takes precedence over WithLoggerContext.
+func WithCallContext(f CallContextProvider) Option {
+	return func(o *Options) {
+		o.CallContextProvider = f
+	}
+}
+
 // WithLevels customizes the function for mapping gRPC return codes and interceptor log level statements.
 func WithLevels(f CodeToLevel) Option {
 	return func(o *Options) {
@@ -82,6 +264,13 @@ func WithLevels(f CodeToLevel) Option {
 	}
 }
 
+// WithCallLevels customizes the function for mapping the call summary to log levels, it takes precedence over WithLevels.
+func WithCallLevels(f CallToLevel) Option {
+	return func(o *Options) {
+		o.CallLevelFunc = f
+	}
+}
+
 // WithCodes customizes the function for mapping errors to error codes.
 func WithCodes(f func(error) codes.Code) Option {
 	return func(o *Options) {
@@ -89,17 +278,337 @@ func WithCodes(f func(error) codes.Code) Option {
 	}
 }
 
+// WithConnDecider customizes the function for deciding if the connection lifecycle should be logged.
+func WithConnDecider(f ConnDecider) Option {
+	return func(o *Options) {
+		o.ConnDecider = f
+	}
+}
+
+// WithStateLevels customizes the function for mapping client connection connectivity states and log level statements.
+func WithStateLevels(f StateToLevel) Option {
+	return func(o *Options) {
+		o.StateLevelFunc = f
+	}
+}
+
+// WithMetadata customizes the function for mapping the logged metadata keys to field names.
+func WithMetadata(f MetadataMapper) Option {
+	return func(o *Options) {
+		o.MetadataMapper = f
+	}
+}
+
+// WithMetadataRedactor customizes the function for redacting the logged metadata values.
+func WithMetadataRedactor(f MetadataRedactor) Option {
+	return func(o *Options) {
+		o.MetadataRedactor = f
+	}
+}
+
+// WithStatusDetailRenderers adds renderers for custom status detail types, they take precedence over the default ones.
+func WithStatusDetailRenderers(renderers ...StatusDetailRenderer) Option {
+	return func(o *Options) {
+		o.StatusDetailRenderers = append(renderers[:len(renderers):len(renderers)], o.StatusDetailRenderers...)
+	}
+}
+
+// WithShutdownContext customizes the context that is done when the server is shutting down, canceled calls will be classified as server_shutdown.
+func WithShutdownContext(ctx context.Context) Option {
+	return func(o *Options) {
+		o.ShutdownContext = ctx
+	}
+}
+
+// WithCancelCauseLevels customizes the function for mapping cancel causes and interceptor log level statements.
+func WithCancelCauseLevels(f CancelCauseToLevel) Option {
+	return func(o *Options) {
+		o.CancelCauseLevelFunc = f
+	}
+}
+
+// WithClientDeadlineGrace customizes the delay before the call deadline during which a cancellation is classified as client_deadline (default 50ms),
+// the client hang up on its own deadline reaches the server a bit before the server side deadline expires because of the network latency.
+func WithClientDeadlineGrace(grace time.Duration) Option {
+	return func(o *Options) {
+		o.ClientDeadlineGrace = grace
+	}
+}
+
+// WithDeadlineWarning escalates the log level to warning when the call finish with less than the given ratio of its deadline budget left (eg: 0.1 for 10%).
+func WithDeadlineWarning(ratio float64) Option {
+	return func(o *Options) {
+		o.DeadlineWarningRatio = ratio
+	}
+}
+
+// WithTimeLayout customizes the layout used to format the times (eg: time.RFC3339Nano).
+func WithTimeLayout(layout string) Option {
+	return func(o *Options) {
+		o.TimeLayout = layout
+	}
+}
+
+// WithTimeLocation customizes the location used to format the times (eg: time.UTC), nil keeps the time location.
+func WithTimeLocation(loc *time.Location) Option {
+	return func(o *Options) {
+		o.TimeLocation = loc
+	}
+}
+
+// WithDurationFormatter customizes the function for representing the durations (eg: DurationMilliseconds).
+func WithDurationFormatter(f DurationFormatter) Option {
+	return func(o *Options) {
+		o.DurationFormatter = f
+	}
+}
+
+// WithMessageDuration customizes if the default log messages embed the duration.
+func WithMessageDuration(enabled bool) Option {
+	return func(o *Options) {
+		o.MessageDuration = enabled
+	}
+}
+
+// WithFieldSchema customizes the logged field names (eg: OTelFieldSchema, ECSFieldSchema).
+func WithFieldSchema(schema FieldSchema) Option {
+	return func(o *Options) {
+		o.FieldSchema = schema
+	}
+}
+
+// WithFieldNames customizes the logged field names on top of the current field schema.
+func WithFieldNames(names map[string]string) Option {
+	return func(o *Options) {
+		schemaNames := make(map[string]string, len(o.FieldSchema.Names)+len(names))
+		for name, schemaName := range o.FieldSchema.Names {
+			schemaNames[name] = schemaName
+		}
+		for name, schemaName := range names {
+			schemaNames[name] = schemaName
+		}
+		o.FieldSchema = FieldSchema{Names: schemaNames, Convert: o.FieldSchema.Convert}
+	}
+}
+
+// WithMessageFormatter customizes the function for building the log messages.
+func WithMessageFormatter(f MessageFormatter) Option {
+	return func(o *Options) {
+		o.MessageFormatter = f
+	}
+}
+
+// WithHooks appends hooks called at the call start, on each stream message and at the call completion.
+func WithHooks(hooks ...Hook) Option {
+	return func(o *Options) {
+		o.Hooks = append(o.Hooks, hooks...)
+	}
+}
+
+// WithStreamHeartbeat enables a periodic "stream still active" entry for the streams, 0 disables it.
+func WithStreamHeartbeat(interval time.Duration) Option {
+	return func(o *Options) {
+		o.HeartbeatInterval = interval
+	}
+}
+
+// WithStreamIdleTimeout logs a warning when no stream message was sent or received during the timeout, 0 disables it.
+func WithStreamIdleTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.StreamIdleTimeout = timeout
+	}
+}
+
+// WithSendStallThreshold logs a warning when a single stream send blocks longer than the threshold (flow-control backpressure), 0 disables it.
+func WithSendStallThreshold(threshold time.Duration) Option {
+	return func(o *Options) {
+		o.SendStallThreshold = threshold
+	}
+}
+
+// WithSlowCalls escalates the level to warning and adds the grpc_slow field for the calls slower than their threshold.
+func WithSlowCalls(f SlowCallThreshold) Option {
+	return func(o *Options) {
+		o.SlowCallThreshold = f
+	}
+}
+
+// WithFieldExtractors appends extractors of the request fields logged on the unary calls and from the first stream request message.
+func WithFieldExtractors(extractors ...FieldExtractor) Option {
+	return func(o *Options) {
+		o.FieldExtractors = append(o.FieldExtractors, extractors...)
+	}
+}
+
+// WithPayloadEncoder customizes the function for logging the stream message payloads.
+func WithPayloadEncoder(f PayloadEncoder) Option {
+	return func(o *Options) {
+		o.PayloadEncoder = f
+	}
+}
+
+// WithPayloadLimits truncates the logged stream message payloads to the given limits.
+func WithPayloadLimits(limits PayloadLimits) Option {
+	return WithPayloadEncoder(TruncatedPayload(limits))
+}
+
+// WithPayloadHash logs a stable hash and the type name of the payloads instead of their content, the hash is keyed when a key is given.
+// It also logs the unary payloads as WithUnaryPayloads(true) does, so the retried unary calls can be spotted.
+func WithPayloadHash(key []byte) Option {
+	return func(o *Options) {
+		o.PayloadEncoder = HashedPayload(key)
+		o.UnaryPayloads = true
+	}
+}
+
+// WithUnaryPayloads logs the unary request and response payloads with the payload encoder.
+func WithUnaryPayloads(enabled bool) Option {
+	return func(o *Options) {
+		o.UnaryPayloads = enabled
+	}
+}
+
+// WithLevelEnabled customizes the function for deciding if the entries of a level are handled by the logger.
+func WithLevelEnabled(f LevelEnabled) Option {
+	return func(o *Options) {
+		o.LevelEnabled = f
+	}
+}
+
+// WithMinLevel skips the construction of the entries less severe than the given level, it should match the logger level filter.
+func WithMinLevel(min logger.Level) Option {
+	return WithLevelEnabled(MinLevel(min))
+}
+
+// WithPooledContexts reuses the logger contexts once their entries are logged,
+// only enable it when the logger handlers don't retain the entry context after the Log call (eg: buffering or asynchronous handlers)
+func WithPooledContexts(enabled bool) Option {
+	return func(o *Options) {
+		o.PooledContexts = enabled
+	}
+}
+
+// WithRateLimit suppresses the call entries over the rate limit of their method and code,
+// a summary of the suppressed entries is logged every summary interval (10 seconds by default).
+func WithRateLimit(f RateLimitFunc) Option {
+	return func(o *Options) {
+		o.RateLimitFunc = f
+	}
+}
+
+// WithRateLimitSummaryInterval customizes the interval the suppressed entries summaries are logged at.
+func WithRateLimitSummaryInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.RateLimitSummaryInterval = interval
+	}
+}
+
+// Enabled returns true when the entries of the given level are handled by the logger
+func (o *Options) Enabled(level logger.Level) bool {
+	return o.LevelEnabled(level)
+}
+
+// DispatchMessage returns true when a stream message must be passed to the call hooks,
+// the successful messages are only logged at debug level so they are skipped when it is disabled and no other hook is configured,
+// the stream completion (counters, headers and trailers) is logged by OnFinish so it never depends on the message entries
+func (o *Options) DispatchMessage(eventType EventType) bool {
+	if len(o.Hooks) > 0 || o.Enabled(logger.DebugLevel) {
+		return true
+	}
+	return eventType == EventSendError || eventType == EventReceiveError
+}
+
+// FeedMetadata adds the metadata keys accepted by the MetadataMapper to the logger context
+func FeedMetadata(loggerContext *logger.Context, fieldPrefix string, md metadata.MD, o *Options) *logger.Context {
+	for key, values := range md {
+		if fieldName, ok := o.MetadataMapper(key); ok {
+			loggerContext.Add(fieldPrefix+fieldName, strings.Join(o.MetadataRedactor(key, values), ","))
+		}
+	}
+	return loggerContext
+}
+
+// RedactMetadata returns a copy of the metadata with the values redacted by the MetadataRedactor
+func RedactMetadata(md metadata.MD, o *Options) metadata.MD {
+	redacted := make(metadata.MD, len(md))
+	for key, values := range md {
+		redacted[key] = o.MetadataRedactor(key, values)
+	}
+	return redacted
+}
+
+// defaultOptions are used by the helpers called without options
+var defaultOptions = evaluateOpt(nil)
+
+// FeedContext adds the method, the start time and the deadline of the call to the logger context using the default options
 func FeedContext(loggerContext *logger.Context, ctx context.Context, fullMethod string, startTime time.Time) *logger.Context {
+	return FeedCallContext(loggerContext, ctx, fullMethod, startTime, defaultOptions)
+}
+
+// FeedCallContext adds the method, the start time and the deadline of the call to the logger context,
+// the method fields are computed once per method and the times once per second when the time layout has no fractional seconds
+func FeedCallContext(loggerContext *logger.Context, ctx context.Context, fullMethod string, startTime time.Time, o *Options) *logger.Context {
 	if loggerContext == nil {
 		loggerContext = logger.NewContext()
 	}
+	method := o.methods.get(fullMethod)
 	loggerContext.
-		Add("grpc_service", path.Dir(fullMethod)[1:]).
-		Add("grpc_method", path.Base(fullMethod)).
-		Add("grpc_start_time", startTime.Format(time.RFC3339))
+		Set("grpc_service", method.service).
+		Set("grpc_method", method.method).
+		Set("grpc_start_time", o.timeField(o.startTimes, startTime))
 
 	if d, ok := ctx.Deadline(); ok {
-		loggerContext.Add("grpc_request_deadline", d.Format(time.RFC3339))
+		loggerContext.
+			Set("grpc_request_deadline", o.timeField(o.deadlines, d)).
+			Set("grpc_deadline_budget", o.durationField(d.Sub(startTime)))
 	}
 	return loggerContext
 }
+
+// FeedDeadlineBudget adds the fraction of the deadline budget consumed by the call to the logger context,
+// it returns true when the call finished within the deadline warning ratio
+func FeedDeadlineBudget(loggerContext *logger.Context, ctx context.Context, startTime time.Time, duration time.Duration, o *Options) bool {
+	d, ok := ctx.Deadline()
+	if !ok {
+		return false
+	}
+	budget := d.Sub(startTime)
+	if budget <= 0 {
+		return false
+	}
+	used := float64(duration) / float64(budget)
+	loggerContext.Set("grpc_deadline_budget_used", logger.Float64(used))
+	if o.DeadlineWarningRatio > 0 && 1-used <= o.DeadlineWarningRatio {
+		loggerContext.Add("grpc_deadline_near", true)
+		return true
+	}
+	return false
+}
+
+// FeedSlowCall adds the grpc_slow field to the logger context when the call has a slow threshold,
+// it returns true when the call duration exceeds it
+func FeedSlowCall(loggerContext *logger.Context, fullMethod string, callType string, duration time.Duration, o *Options) bool {
+	threshold := o.SlowCallThreshold(fullMethod, callType)
+	if threshold <= 0 {
+		return false
+	}
+	slow := duration > threshold
+	loggerContext.Add("grpc_slow", slow)
+	return slow
+}
+
+// ErrorLevel returns the code and the log level of an error returned during the call (eg: a failed stream operation)
+func (o *Options) ErrorLevel(info CallInfo, err error) (codes.Code, logger.Level) {
+	info.Code = o.CodeFunc(err)
+	info.Err = err
+	info.Duration = time.Since(info.StartTime)
+	return info.Code, o.CallLevelFunc(&info)
+}
+
+// EscalateLevel returns the most severe level between the given level and the minimum one
+func EscalateLevel(level logger.Level, min logger.Level) logger.Level {
+	if level > min {
+		return min
+	}
+	return level
+}
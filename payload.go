@@ -0,0 +1,171 @@
+package logger_grpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"unicode/utf8"
+
+	"github.com/gol4ng/logger"
+	"github.com/golang/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TruncationMarker is appended to the truncated payloads and strings
+const TruncationMarker = "...[truncated]"
+
+// PayloadEncoder function adds the message payload to the logger context under the given field name
+type PayloadEncoder func(loggerContext *logger.Context, fieldName string, message interface{}) *logger.Context
+
+// RawPayload is a PayloadEncoder adding the message as is
+func RawPayload(loggerContext *logger.Context, fieldName string, message interface{}) *logger.Context {
+	return loggerContext.Add(fieldName, message)
+}
+
+// PayloadLimits defines the payload truncation limits, a zero limit means unlimited
+type PayloadLimits struct {
+	// MaxSize is the maximum JSON serialized size of the logged payload
+	MaxSize int
+	// MaxStringLength is the maximum length in bytes of the string fields, they are cut on a character boundary
+	MaxStringLength int
+	// MaxBytesLength is the maximum length of the bytes fields, they are only marked by the "_truncated" field
+	MaxBytesLength int
+	// MaxRepeated is the maximum number of items of the repeated and map fields
+	MaxRepeated int
+}
+
+// TruncatedPayload returns a PayloadEncoder truncating the payload to the given limits.
+// The proto messages are logged as a map of their set fields, the "_size" suffixed field contains the original serialized size
+// and the "_truncated" suffixed field is added when the payload was truncated.
+func TruncatedPayload(limits PayloadLimits) PayloadEncoder {
+	return func(loggerContext *logger.Context, fieldName string, message interface{}) *logger.Context {
+		truncated := false
+		var payload interface{} = message
+		if m, ok := message.(proto.Message); ok {
+			loggerContext.Add(fieldName+"_size", proto.Size(m))
+			payload = limits.messageMap(proto.MessageReflect(m), &truncated)
+		}
+		if limits.MaxSize > 0 {
+			if serialized, err := json.Marshal(payload); err == nil && len(serialized) > limits.MaxSize {
+				payload = truncateString(string(serialized), limits.MaxSize) + TruncationMarker
+				truncated = true
+			}
+		}
+		if truncated {
+			loggerContext.Add(fieldName+"_truncated", true)
+		}
+		return loggerContext.Add(fieldName, payload)
+	}
+}
+
+func (l PayloadLimits) messageMap(msg protoreflect.Message, truncated *bool) map[string]interface{} {
+	fields := map[string]interface{}{}
+	msg.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		fields[string(fd.Name())] = l.fieldValue(fd, value, truncated)
+		return true
+	})
+	return fields
+}
+
+func (l PayloadLimits) fieldValue(fd protoreflect.FieldDescriptor, value protoreflect.Value, truncated *bool) interface{} {
+	if fd.IsList() {
+		list := value.List()
+		length := list.Len()
+		if l.MaxRepeated > 0 && length > l.MaxRepeated {
+			length = l.MaxRepeated
+			*truncated = true
+		}
+		values := make([]interface{}, 0, length+1)
+		for i := 0; i < length; i++ {
+			values = append(values, l.singularValue(fd, list.Get(i), truncated))
+		}
+		if length < list.Len() {
+			values = append(values, fmt.Sprintf("%s %d more", TruncationMarker, list.Len()-length))
+		}
+		return values
+	}
+	if fd.IsMap() {
+		values := map[string]interface{}{}
+		count := 0
+		value.Map().Range(func(key protoreflect.MapKey, v protoreflect.Value) bool {
+			if l.MaxRepeated > 0 && count >= l.MaxRepeated {
+				*truncated = true
+				return false
+			}
+			values[key.String()] = l.singularValue(fd.MapValue(), v, truncated)
+			count++
+			return true
+		})
+		return values
+	}
+	return l.singularValue(fd, value, truncated)
+}
+
+func (l PayloadLimits) singularValue(fd protoreflect.FieldDescriptor, value protoreflect.Value, truncated *bool) interface{} {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		s := value.String()
+		if l.MaxStringLength > 0 && len(s) > l.MaxStringLength {
+			*truncated = true
+			return truncateString(s, l.MaxStringLength) + TruncationMarker
+		}
+		return s
+	case protoreflect.BytesKind:
+		b := value.Bytes()
+		if l.MaxBytesLength > 0 && len(b) > l.MaxBytesLength {
+			*truncated = true
+			// a marker in the bytes would be unreadable once base64 encoded
+			return b[:l.MaxBytesLength:l.MaxBytesLength]
+		}
+		return b
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return l.messageMap(value.Message(), truncated)
+	}
+	return protoScalarValue(fd, value)
+}
+
+// truncateString returns the first maxLength bytes of s, cut back to a UTF-8 character boundary
+func truncateString(s string, maxLength int) string {
+	for maxLength > 0 && !utf8.RuneStart(s[maxLength]) {
+		maxLength--
+	}
+	return s[:maxLength]
+}
+
+// HashedPayload returns a PayloadEncoder logging the message type name and a stable SHA-256 hash of the payload instead of its content
+// (in the "_type" and "_hash" suffixed fields), the hash is a HMAC-SHA256 when a key is given.
+// The proto messages are hashed from their deterministic serialization, the other messages from their JSON serialization.
+func HashedPayload(key []byte) PayloadEncoder {
+	return func(loggerContext *logger.Context, fieldName string, message interface{}) *logger.Context {
+		if message == nil {
+			return loggerContext
+		}
+		typeName, data, err := serializePayload(message)
+		loggerContext.Add(fieldName+"_type", typeName)
+		if err != nil {
+			return loggerContext
+		}
+		var h hash.Hash
+		if len(key) > 0 {
+			h = hmac.New(sha256.New, key)
+		} else {
+			h = sha256.New()
+		}
+		_, _ = h.Write(data)
+		return loggerContext.Add(fieldName+"_hash", hex.EncodeToString(h.Sum(nil)))
+	}
+}
+
+func serializePayload(message interface{}) (typeName string, data []byte, err error) {
+	if m, ok := message.(proto.Message); ok {
+		m2 := proto.MessageV2(m)
+		data, err = protov2.MarshalOptions{Deterministic: true}.Marshal(m2)
+		return string(m2.ProtoReflect().Descriptor().FullName()), data, err
+	}
+	data, err = json.Marshal(message)
+	return fmt.Sprintf("%T", message), data, err
+}
@@ -0,0 +1,36 @@
+package logger_grpc
+
+import (
+	"sync"
+
+	"github.com/gol4ng/logger"
+)
+
+// maxPooledContextSize bounds the size of the logger contexts put back in the pool
+const maxPooledContextSize = 64
+
+var contextPool = sync.Pool{
+	New: func() interface{} {
+		return &logger.Context{}
+	},
+}
+
+// AcquireContext returns an empty logger context, it comes from the context pool when the pooled contexts are enabled
+func (o *Options) AcquireContext() *logger.Context {
+	if !o.PooledContexts {
+		return &logger.Context{}
+	}
+	return contextPool.Get().(*logger.Context)
+}
+
+// ReleaseContext puts the logger context back in the pool when the pooled contexts are enabled,
+// the logger context must not be used once released
+func (o *Options) ReleaseContext(loggerContext *logger.Context) {
+	if !o.PooledContexts || loggerContext == nil || len(*loggerContext) > maxPooledContextSize {
+		return
+	}
+	for name := range *loggerContext {
+		delete(*loggerContext, name)
+	}
+	contextPool.Put(loggerContext)
+}
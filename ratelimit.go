@@ -0,0 +1,236 @@
+package logger_grpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gol4ng/logger"
+	"google.golang.org/grpc/codes"
+)
+
+// maxRateLimitBuckets is the number of buckets over which the full buckets are released on each entry
+const maxRateLimitBuckets = 1024
+
+// RateLimit defines the token bucket of the entries of a method and code,
+// Rate entries per second are allowed with bursts of Burst entries (at least 1), the entries are not limited when Rate is 0
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitFunc function defines the rate limit of the entries given the full method and the code
+type RateLimitFunc func(fullMethod string, code codes.Code) RateLimit
+
+// ErrorRateLimit returns a RateLimitFunc limiting the entries of every method and code except codes.OK
+func ErrorRateLimit(limit RateLimit) RateLimitFunc {
+	return func(fullMethod string, code codes.Code) RateLimit {
+		if code == codes.OK {
+			return RateLimit{}
+		}
+		return limit
+	}
+}
+
+type rateLimitKey struct {
+	kind       string
+	callType   string
+	fullMethod string
+	code       codes.Code
+}
+
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+	limit  RateLimit
+	// suppressed is the number of entries suppressed since the last summary,
+	// the summary is logged with the logger and at the most severe level of the suppressed entries
+	suppressed int64
+	level      logger.Level
+	logger     logger.LoggerInterface
+	// removeCloseHook unregisters the pending summary from the CloseNotifier logger it was started with
+	removeCloseHook func()
+}
+
+// refill adds the tokens earned since the last entry
+func (b *rateBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.limit.Rate
+	if b.tokens > float64(b.limit.Burst) {
+		b.tokens = float64(b.limit.Burst)
+	}
+	b.last = now
+}
+
+// rateLimiter suppresses the entries over the rate limit of their method and code,
+// it logs a summary of the suppressed entries every summary interval while entries are suppressed.
+// The pending summaries of the CloseNotifier loggers are logged when they are closed, the summaries of the other loggers
+// are logged by the report goroutine so they are lost when their logger is closed before the next summary interval.
+type rateLimiter struct {
+	options   *Options
+	mu        sync.Mutex
+	buckets   map[rateLimitKey]*rateBucket
+	reporting bool
+}
+
+func newRateLimiter(o *Options) *rateLimiter {
+	return &rateLimiter{
+		options: o,
+		buckets: map[rateLimitKey]*rateBucket{},
+	}
+}
+
+// allow returns true when the entry is within the rate limit of its method and code, otherwise it is counted in the next summary
+func (r *rateLimiter) allow(log logger.LoggerInterface, info *CallInfo, code codes.Code, level logger.Level) bool {
+	limit := r.options.RateLimitFunc(info.FullMethod, code)
+	if limit.Rate <= 0 {
+		return true
+	}
+	// a bucket without burst would never hold a token
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	now := time.Now()
+	key := rateLimitKey{kind: info.Kind, callType: info.CallType, fullMethod: info.FullMethod, code: code}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bucket, ok := r.buckets[key]
+	if !ok {
+		if len(r.buckets) >= maxRateLimitBuckets {
+			r.releaseFullBuckets(now)
+		}
+		bucket = &rateBucket{tokens: float64(limit.Burst), last: now}
+		r.buckets[key] = bucket
+	}
+	bucket.limit = limit
+	bucket.refill(now)
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true
+	}
+	if bucket.suppressed == 0 || level < bucket.level {
+		bucket.level = level
+	}
+	if bucket.suppressed == 0 {
+		r.watchClose(key, bucket, log)
+	}
+	bucket.suppressed++
+	bucket.logger = log
+	if !r.reporting {
+		r.reporting = true
+		go r.report()
+	}
+	return false
+}
+
+// releaseFullBuckets removes the buckets equivalent to new ones
+func (r *rateLimiter) releaseFullBuckets(now time.Time) {
+	for key, bucket := range r.buckets {
+		bucket.refill(now)
+		if bucket.suppressed == 0 && bucket.tokens >= float64(bucket.limit.Burst) {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// report logs the summaries every summary interval, it returns once an interval has no suppressed entry
+func (r *rateLimiter) report() {
+	ticker := time.NewTicker(r.options.RateLimitSummaryInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !r.summarize() {
+			return
+		}
+	}
+}
+
+// watchClose logs the pending summary of the bucket before its logger is closed when the logger is a CloseNotifier,
+// it must be called with the lock held when the bucket starts a new summary
+func (r *rateLimiter) watchClose(key rateLimitKey, bucket *rateBucket, log logger.LoggerInterface) {
+	if notifier, ok := log.(CloseNotifier); ok {
+		bucket.removeCloseHook = notifier.OnClose(func() {
+			r.summarizeBucket(key)
+		})
+	}
+}
+
+// rateSummary is the summary of the entries suppressed in a bucket since the last summary
+type rateSummary struct {
+	key        rateLimitKey
+	suppressed int64
+	level      logger.Level
+	logger     logger.LoggerInterface
+}
+
+// summarize logs the summary of the suppressed entries of each bucket, it returns false when no entry was suppressed
+func (r *rateLimiter) summarize() bool {
+	r.mu.Lock()
+	summaries := r.collect()
+	r.releaseFullBuckets(time.Now())
+	if len(summaries) == 0 {
+		r.reporting = false
+	}
+	r.mu.Unlock()
+
+	r.log(summaries)
+	return len(summaries) > 0
+}
+
+// summarizeBucket logs the pending summary of a bucket
+func (r *rateLimiter) summarizeBucket(key rateLimitKey) {
+	r.mu.Lock()
+	var summaries []rateSummary
+	if bucket, ok := r.buckets[key]; ok && bucket.suppressed > 0 {
+		summaries = append(summaries, bucket.reset(key))
+	}
+	r.mu.Unlock()
+
+	r.log(summaries)
+}
+
+// collect returns and resets the summaries of the buckets, it must be called with the lock held
+func (r *rateLimiter) collect() []rateSummary {
+	var summaries []rateSummary
+	for key, bucket := range r.buckets {
+		if bucket.suppressed > 0 {
+			summaries = append(summaries, bucket.reset(key))
+		}
+	}
+	return summaries
+}
+
+// reset returns the summary of the bucket and starts a new one
+func (b *rateBucket) reset(key rateLimitKey) rateSummary {
+	summary := rateSummary{key: key, suppressed: b.suppressed, level: b.level, logger: b.logger}
+	if b.removeCloseHook != nil {
+		b.removeCloseHook()
+		b.removeCloseHook = nil
+	}
+	b.suppressed = 0
+	b.logger = nil
+	return summary
+}
+
+func (r *rateLimiter) log(summaries []rateSummary) {
+	interval := r.options.RateLimitSummaryInterval
+	for _, s := range summaries {
+		method := r.options.methods.get(s.key.fullMethod)
+		loggerContext := logger.NewContext().
+			Set("grpc_service", method.service).
+			Set("grpc_method", method.method).
+			Add("grpc_kind", s.key.kind).
+			Set("grpc_code", codeField(s.key.code)).
+			Add("grpc_suppressed", s.suppressed).
+			Add("grpc_suppressed_interval", r.options.FormatDuration(interval))
+		event := &CallEvent{Type: EventSuppressed, Kind: s.key.kind, CallType: s.key.callType, FullMethod: s.key.fullMethod, Code: s.key.code, Duration: interval, Suppressed: s.suppressed}
+		_ = s.logger.Log(r.options.FormatMessage(event), s.level, r.options.ApplySchema(loggerContext))
+	}
+}
+
+// AllowEntry returns true when the entry of the call with the given code is within its rate limit,
+// the suppressed entries are counted and summarized every RateLimitSummaryInterval
+func (o *Options) AllowEntry(log logger.LoggerInterface, info *CallInfo, code codes.Code, level logger.Level) bool {
+	if o.rateLimiter == nil {
+		return true
+	}
+	return o.rateLimiter.allow(log, info, code, level)
+}
@@ -0,0 +1,112 @@
+package logger_grpc
+
+import (
+	"fmt"
+
+	"github.com/gol4ng/logger"
+	"google.golang.org/grpc/codes"
+)
+
+// FieldSchema defines the logged field names and values
+type FieldSchema struct {
+	// Names maps the default field names to the logged ones, the fields missing from it keep their default name
+	Names map[string]string
+	// Convert adapts the field values to the schema before the fields are renamed (the fields still have their default names), it can be nil
+	Convert func(loggerContext *logger.Context)
+}
+
+// DefaultFieldSchema keeps the default grpc_* field names
+var DefaultFieldSchema = FieldSchema{}
+
+// OTelFieldSchema follows the OpenTelemetry rpc semantic conventions,
+// the status code is logged as an integer and every entry has the rpc.system field
+var OTelFieldSchema = FieldSchema{
+	Names: map[string]string{
+		"grpc_service":       "rpc.service",
+		"grpc_method":        "rpc.method",
+		"grpc_code":          "rpc.grpc.status_code",
+		"grpc_error_message": "exception.message",
+		"grpc_peer_address":  "network.peer.address",
+		"grpc_remote_addr":   "network.peer.address",
+		"grpc_local_addr":    "network.local.address",
+		"grpc_target":        "server.address",
+	},
+	Convert: func(loggerContext *logger.Context) {
+		if field, ok := (*loggerContext)["grpc_code"]; ok {
+			if name, ok := field.Value.(string); ok {
+				loggerContext.Set("grpc_code", logger.Int64(int64(codeFromName(name))))
+			}
+		}
+		loggerContext.Set("rpc.system", logger.String("grpc"))
+	},
+}
+
+// ECSFieldSchema follows the Elastic Common Schema, custom grpc fields are namespaced with "grpc."
+// the error and panic messages are logged as error.message and the code name of the failed calls as error.type,
+// ECS expects event.duration in nanoseconds, use it with WithDurationFormatter(DurationNanoseconds)
+var ECSFieldSchema = FieldSchema{
+	Names: map[string]string{
+		"grpc_service":          "grpc.service",
+		"grpc_method":           "grpc.method",
+		"grpc_kind":             "grpc.kind",
+		"grpc_code":             "grpc.code",
+		"grpc_start_time":       "event.start",
+		"grpc_duration":         "event.duration",
+		"grpc_request_deadline": "grpc.request_deadline",
+		"grpc_error":            "error.message",
+		"grpc_panic":            "error.message",
+		"grpc_peer_address":     "destination.address",
+		"grpc_remote_addr":      "source.address",
+		"grpc_local_addr":       "destination.address",
+		"grpc_target":           "url.original",
+	},
+	Convert: func(loggerContext *logger.Context) {
+		if field, ok := (*loggerContext)["grpc_panic"]; ok {
+			loggerContext.Set("grpc_panic", logger.String(fmt.Sprint(field.Value)))
+			loggerContext.Set("error.type", logger.String("panic"))
+		}
+		if field, ok := (*loggerContext)["grpc_error"]; ok {
+			// grpc_error_message holds the same message
+			delete(*loggerContext, "grpc_error_message")
+			if err, ok := field.Value.(error); ok {
+				loggerContext.Set("grpc_error", logger.String(err.Error()))
+			}
+			if code, ok := (*loggerContext)["grpc_code"]; ok {
+				loggerContext.Set("error.type", code)
+			}
+		}
+	},
+}
+
+// codeFromName returns the code of the given code name
+func codeFromName(name string) codes.Code {
+	for code, field := range codeFields {
+		if field.Value == name {
+			return codes.Code(code)
+		}
+	}
+	var code codes.Code
+	_, _ = fmt.Sscanf(name, "Code(%d)", &code)
+	return code
+}
+
+// ApplySchema returns the logger context with the fields converted and renamed according to the field schema
+func (o *Options) ApplySchema(loggerContext *logger.Context) *logger.Context {
+	if (len(o.FieldSchema.Names) == 0 && o.FieldSchema.Convert == nil) || loggerContext == nil {
+		return loggerContext
+	}
+	if o.FieldSchema.Convert != nil {
+		converted := make(logger.Context, len(*loggerContext)+1)
+		converted.Merge(*loggerContext)
+		o.FieldSchema.Convert(&converted)
+		loggerContext = &converted
+	}
+	renamed := make(logger.Context, len(*loggerContext))
+	for name, field := range *loggerContext {
+		if schemaName, ok := o.FieldSchema.Names[name]; ok {
+			name = schemaName
+		}
+		renamed[name] = field
+	}
+	return &renamed
+}
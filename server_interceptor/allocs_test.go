@@ -0,0 +1,45 @@
+//go:build !race
+// +build !race
+
+package server_interceptor_test
+
+import (
+	"testing"
+
+	"github.com/gol4ng/logger"
+	"github.com/stretchr/testify/assert"
+
+	logger_grpc "github.com/gol4ng/logger-grpc"
+	"github.com/gol4ng/logger-grpc/server_interceptor"
+)
+
+// allocation targets per call, the stream calls exchange 10 request/response messages with the debug entries disabled.
+// The targets keep about 25% headroom over the measured allocations (17, 11, 49 and 41) to survive grpc and Go updates.
+const (
+	unaryAllocsTarget        = 22
+	unaryPooledAllocsTarget  = 14
+	streamAllocsTarget       = 62
+	streamPooledAllocsTarget = 52
+)
+
+func TestUnaryInterceptor_Allocations(t *testing.T) {
+	ctx, cancel := benchmarkContext()
+	defer cancel()
+
+	call := unaryInterceptorCall(server_interceptor.UnaryInterceptor(benchmarkLogger()))
+	assert.LessOrEqual(t, testing.AllocsPerRun(100, func() { call(ctx) }), float64(unaryAllocsTarget))
+
+	call = unaryInterceptorCall(server_interceptor.UnaryInterceptor(benchmarkLogger(), logger_grpc.WithPooledContexts(true)))
+	assert.LessOrEqual(t, testing.AllocsPerRun(100, func() { call(ctx) }), float64(unaryPooledAllocsTarget))
+}
+
+func TestStreamInterceptor_Allocations(t *testing.T) {
+	ctx, cancel := benchmarkContext()
+	defer cancel()
+
+	call := streamInterceptorCall(server_interceptor.StreamInterceptor(benchmarkLogger(), logger_grpc.WithMinLevel(logger.InfoLevel)))
+	assert.LessOrEqual(t, testing.AllocsPerRun(100, func() { call(ctx) }), float64(streamAllocsTarget))
+
+	call = streamInterceptorCall(server_interceptor.StreamInterceptor(benchmarkLogger(), logger_grpc.WithMinLevel(logger.InfoLevel), logger_grpc.WithPooledContexts(true)))
+	assert.LessOrEqual(t, testing.AllocsPerRun(100, func() { call(ctx) }), float64(streamPooledAllocsTarget))
+}
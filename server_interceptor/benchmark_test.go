@@ -0,0 +1,85 @@
+package server_interceptor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gol4ng/logger"
+	"github.com/gol4ng/logger/middleware"
+	pb_testproto "github.com/grpc-ecosystem/go-grpc-middleware/testing/testproto"
+	"google.golang.org/grpc"
+
+	logger_grpc "github.com/gol4ng/logger-grpc"
+	"github.com/gol4ng/logger-grpc/server_interceptor"
+)
+
+func benchmarkLogger() logger.LoggerInterface {
+	return logger.NewLogger(middleware.MinLevelFilter(logger.InfoLevel)(logger.NopHandler))
+}
+
+func benchmarkContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), time.Hour)
+}
+
+func unaryInterceptorCall(interceptor grpc.UnaryServerInterceptor) func(ctx context.Context) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/mwitkow.testproto.TestService/Ping"}
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	pingResponse := &pb_testproto.PingResponse{Value: "my_fake_ping_payload"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return pingResponse, nil
+	}
+	return func(ctx context.Context) {
+		_, _ = interceptor(ctx, pingRequest, info, handler)
+	}
+}
+
+func streamInterceptorCall(interceptor grpc.StreamServerInterceptor) func(ctx context.Context) {
+	info := &grpc.StreamServerInfo{FullMethod: "/mwitkow.testproto.TestService/PingStream", IsClientStream: true, IsServerStream: true}
+	pingRequest := &pb_testproto.PingRequest{}
+	pingResponse := &pb_testproto.PingResponse{Value: "my_fake_ping_payload"}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		for i := 0; i < 10; i++ {
+			_ = stream.RecvMsg(pingRequest)
+			_ = stream.SendMsg(pingResponse)
+		}
+		return nil
+	}
+	return func(ctx context.Context) {
+		_ = interceptor(nil, &benchmarkServerStream{ctx: ctx}, info, handler)
+	}
+}
+
+func runBenchmark(b *testing.B, call func(ctx context.Context)) {
+	ctx, cancel := benchmarkContext()
+	defer cancel()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		call(ctx)
+	}
+}
+
+// BenchmarkUnaryInterceptor measures a unary call logged at info level
+func BenchmarkUnaryInterceptor(b *testing.B) {
+	b.Run("default", func(b *testing.B) {
+		runBenchmark(b, unaryInterceptorCall(server_interceptor.UnaryInterceptor(benchmarkLogger())))
+	})
+	b.Run("pooled contexts", func(b *testing.B) {
+		runBenchmark(b, unaryInterceptorCall(server_interceptor.UnaryInterceptor(benchmarkLogger(), logger_grpc.WithPooledContexts(true))))
+	})
+}
+
+// BenchmarkStreamInterceptor measures a stream call of 10 request/response messages with the debug entries disabled
+func BenchmarkStreamInterceptor(b *testing.B) {
+	b.Run("default", func(b *testing.B) {
+		runBenchmark(b, streamInterceptorCall(server_interceptor.StreamInterceptor(benchmarkLogger(), logger_grpc.WithMinLevel(logger.InfoLevel))))
+	})
+	b.Run("pooled contexts", func(b *testing.B) {
+		runBenchmark(b, streamInterceptorCall(server_interceptor.StreamInterceptor(benchmarkLogger(), logger_grpc.WithMinLevel(logger.InfoLevel), logger_grpc.WithPooledContexts(true))))
+	})
+	b.Run("debug enabled", func(b *testing.B) {
+		runBenchmark(b, streamInterceptorCall(server_interceptor.StreamInterceptor(logger.NewNopLogger(), logger_grpc.WithPooledContexts(true))))
+	})
+}
@@ -0,0 +1,73 @@
+package server_interceptor
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataRecorder records the headers and trailers sent by the server handler
+type metadataRecorder struct {
+	mu      sync.Mutex
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (r *metadataRecorder) addHeader(md metadata.MD) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.header = metadata.Join(r.header, md)
+}
+
+func (r *metadataRecorder) addTrailer(md metadata.MD) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.trailer = metadata.Join(r.trailer, md)
+}
+
+func (r *metadataRecorder) metadata() (header metadata.MD, trailer metadata.MD) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.header, r.trailer
+}
+
+// transportStreamWrapper records the metadata set with grpc.SetHeader, grpc.SendHeader and grpc.SetTrailer
+type transportStreamWrapper struct {
+	grpc.ServerTransportStream
+	recorder *metadataRecorder
+}
+
+func (t *transportStreamWrapper) SetHeader(md metadata.MD) error {
+	err := t.ServerTransportStream.SetHeader(md)
+	if err == nil {
+		t.recorder.addHeader(md)
+	}
+	return err
+}
+
+func (t *transportStreamWrapper) SendHeader(md metadata.MD) error {
+	err := t.ServerTransportStream.SendHeader(md)
+	if err == nil {
+		t.recorder.addHeader(md)
+	}
+	return err
+}
+
+func (t *transportStreamWrapper) SetTrailer(md metadata.MD) error {
+	err := t.ServerTransportStream.SetTrailer(md)
+	if err == nil {
+		t.recorder.addTrailer(md)
+	}
+	return err
+}
+
+// withMetadataRecorder returns a context where the server transport stream records the sent metadata
+func withMetadataRecorder(ctx context.Context, recorder *metadataRecorder) context.Context {
+	stream := grpc.ServerTransportStreamFromContext(ctx)
+	if stream == nil {
+		return ctx
+	}
+	return grpc.NewContextWithServerTransportStream(ctx, &transportStreamWrapper{ServerTransportStream: stream, recorder: recorder})
+}
@@ -1,11 +1,11 @@
 package server_interceptor
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/gol4ng/logger"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 
 	logger_grpc "github.com/gol4ng/logger-grpc"
 )
@@ -17,31 +17,48 @@ func StreamInterceptor(log logger.LoggerInterface, opts ...logger_grpc.Option) g
 		ctx := stream.Context()
 		startTime := time.Now()
 
+		callInfo := &logger_grpc.CallInfo{FullMethod: info.FullMethod, Kind: "server", CallType: "stream", StartTime: startTime}
+		if p, ok := peer.FromContext(ctx); ok {
+			callInfo.Peer = p.Addr
+		}
 		currentLogger := logger.FromContext(ctx, log)
-		currentLoggerContext := logger_grpc.FeedContext(o.LoggerContextProvider(info.FullMethod), ctx, info.FullMethod, startTime).Add("grpc_kind", "server")
+		currentLoggerContext := o.CallContextProvider(ctx, callInfo, nil)
+		if currentLoggerContext == nil {
+			currentLoggerContext = o.AcquireContext()
+			defer o.ReleaseContext(currentLoggerContext)
+		}
+		logger_grpc.FeedCallContext(currentLoggerContext, ctx, info.FullMethod, startTime, o).Set("grpc_kind", kindField)
+		streamWrapper := NewServerStreamWrapper(stream, ctx, o, currentLogger, currentLoggerContext)
+		streamWrapper.info = callInfo
 
-		defer func() {
-			duration := time.Since(startTime)
-			currentLoggerContext.Add("grpc_duration", duration.Seconds())
+		var stopHeartbeat, stopIdleDetection func()
 
-			if err := recover(); err != nil {
-				currentLoggerContext.Add("grpc_panic", err)
-				_ = currentLogger.Critical(fmt.Sprintf("grpc server stream panic %s [duration:%s]", info.FullMethod, duration), currentLoggerContext)
-				panic(err)
+		defer func() {
+			stopHeartbeat()
+			stopIdleDetection()
+			recovered := recover()
+			// the call info is snapshotted under the lock, the hooks are called without holding it
+			streamWrapper.mu.Lock()
+			callInfo.Duration = time.Since(startTime)
+			callInfo.Header, callInfo.Trailer = streamWrapper.metadata.metadata()
+			if recovered != nil {
+				callInfo.Panic = recovered
+			} else {
+				callInfo.Code = o.CodeFunc(err)
+				callInfo.Err = err
 			}
+			finishInfo := *callInfo
+			streamWrapper.mu.Unlock()
 
-			code := o.CodeFunc(err)
-			codeStr := code.String()
-			currentLoggerContext.Add("grpc_code", codeStr)
-			if err != nil {
-				currentLoggerContext.
-					Add("grpc_error", err).
-					Add("grpc_error_message", err.Error())
+			streamWrapper.hooks.OnFinish(ctx, &finishInfo)
+			if recovered != nil {
+				panic(recovered)
 			}
-
-			_ = currentLogger.Log(fmt.Sprintf("grpc server stream call %s [code:%s, duration:%s]", info.FullMethod, codeStr, duration), o.LevelFunc(code), currentLoggerContext)
 		}()
-		_ = currentLogger.Debug("grpc server begin stream call "+info.FullMethod, currentLoggerContext)
-		return handler(srv, NewServerStreamWrapper(stream, ctx, o, currentLogger, currentLoggerContext))
+
+		streamWrapper.hooks.OnStart(ctx, callInfo)
+		stopHeartbeat = logger_grpc.StartHeartbeat(ctx, currentLogger, currentLoggerContext, o, streamWrapper.snapshot)
+		stopIdleDetection = logger_grpc.StartIdleDetection(ctx, currentLogger, currentLoggerContext, o, streamWrapper.snapshot)
+		return handler(srv, streamWrapper)
 	}
 }
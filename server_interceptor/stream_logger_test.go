@@ -1,16 +1,23 @@
 package server_interceptor_test
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gol4ng/logger"
 	testing_logger "github.com/gol4ng/logger/testing"
+	"github.com/golang/protobuf/proto"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_testing "github.com/grpc-ecosystem/go-grpc-middleware/testing"
 	pb_testproto "github.com/grpc-ecosystem/go-grpc-middleware/testing/testproto"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 
 	logger_grpc "github.com/gol4ng/logger-grpc"
 	"github.com/gol4ng/logger-grpc/server_interceptor"
@@ -252,3 +259,542 @@ func TestStreamInterceptor_WithLevels(t *testing.T) {
 	assert.Equal(t, logger.EmergencyLevel, entry5.Level)
 	assert.Regexp(t, `grpc server stream call /mwitkow\.testproto\.TestService/PingStream \[code:OK, duration:.*\]`, entry5.Message)
 }
+
+func TestStreamInterceptor_WithMetadata(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
+				server_interceptor.StreamInterceptor(myLogger,
+					logger_grpc.WithMetadata(logger_grpc.MetadataKeys("x-request-id", "x-secret", "x-ratelimit-remaining", "x-stream-trailer")),
+					logger_grpc.WithMetadataRedactor(logger_grpc.RedactMetadataKeys("x-secret")),
+				),
+				func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+					_ = stream.SetHeader(metadata.Pairs("x-request-id", "my_request_id"))
+					_ = grpc.SetHeader(stream.Context(), metadata.Pairs("x-secret", "my_secret"))
+					_ = grpc.SetTrailer(stream.Context(), metadata.Pairs("x-ratelimit-remaining", "41"))
+					stream.SetTrailer(metadata.Pairs("x-stream-trailer", "my_trailer"))
+					return handler(srv, stream)
+				},
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "my_fake_ping_payload"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	assert.NoError(t, resp.CloseSend())
+
+	assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 5 }, time.Second, time.Millisecond)
+	entries := myLogger.GetEntries()
+
+	entry5 := entries[4]
+	entry5Ctx := *entry5.Context
+	assert.Regexp(t, `grpc server stream call /mwitkow\.testproto\.TestService/PingStream \[code:OK, duration:.*\]`, entry5.Message)
+	assert.Equal(t, "my_request_id", entry5Ctx["grpc_header_x-request-id"].Value)
+	assert.Equal(t, "[REDACTED]", entry5Ctx["grpc_header_x-secret"].Value)
+	assert.Equal(t, "41", entry5Ctx["grpc_trailer_x-ratelimit-remaining"].Value)
+	assert.Equal(t, "my_trailer", entry5Ctx["grpc_trailer_x-stream-trailer"].Value)
+}
+
+func TestStreamInterceptor_WithHooks(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+	var started []string
+	var messages []logger_grpc.EventType
+	var finished []logger_grpc.CallInfo
+	mu := sync.Mutex{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(server_interceptor.StreamInterceptor(myLogger, logger_grpc.WithHooks(logger_grpc.HookFuncs{
+				Start: func(ctx context.Context, info *logger_grpc.CallInfo) {
+					mu.Lock()
+					defer mu.Unlock()
+					started = append(started, info.FullMethod)
+				},
+				Message: func(ctx context.Context, info *logger_grpc.CallInfo, message *logger_grpc.MessageInfo) {
+					mu.Lock()
+					defer mu.Unlock()
+					messages = append(messages, message.Type)
+				},
+				Finish: func(ctx context.Context, info *logger_grpc.CallInfo) {
+					mu.Lock()
+					defer mu.Unlock()
+					finished = append(finished, *info)
+				},
+			}))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	assert.NoError(t, resp.Send(pingRequest))
+	pingResponse, err := resp.Recv()
+	assert.NoError(t, err)
+	assert.NoError(t, resp.CloseSend())
+
+	assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 5 }, time.Second, time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, []string{"/mwitkow.testproto.TestService/PingStream"}, started)
+	assert.Equal(t, []logger_grpc.EventType{logger_grpc.EventReceive, logger_grpc.EventSend, logger_grpc.EventReceiveEOF}, messages)
+	assert.Len(t, finished, 1)
+	info := finished[0]
+	assert.Equal(t, "server", info.Kind)
+	assert.Equal(t, "stream", info.CallType)
+	assert.Equal(t, codes.OK, info.Code)
+	assert.NoError(t, info.Err)
+	assert.NotNil(t, info.Peer)
+	assert.True(t, info.Duration > 0)
+	assert.Equal(t, int64(1), info.ReceivedMessages)
+	assert.Equal(t, int64(1), info.SentMessages)
+	assert.Equal(t, int64(proto.Size(pingRequest)), info.ReceivedBytes)
+	assert.Equal(t, int64(proto.Size(pingResponse)), info.SentBytes)
+}
+
+func TestStreamInterceptor_WithStreamStats(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(server_interceptor.StreamInterceptor(myLogger)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	assert.NoError(t, resp.Send(pingRequest))
+	pingResponse, err := resp.Recv()
+	assert.NoError(t, err)
+	assert.NoError(t, resp.CloseSend())
+
+	assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 5 }, time.Second, time.Millisecond)
+	entries := myLogger.GetEntries()
+
+	entryCtx := *entries[4].Context
+	assert.Equal(t, int64(1), entryCtx["grpc_received_messages"].Value)
+	assert.Equal(t, int64(1), entryCtx["grpc_sent_messages"].Value)
+	assert.Equal(t, int64(proto.Size(pingRequest)), entryCtx["grpc_received_bytes"].Value)
+	assert.Equal(t, int64(proto.Size(pingResponse)), entryCtx["grpc_sent_bytes"].Value)
+	assert.Equal(t, int64(proto.Size(pingRequest)), entryCtx["grpc_max_received_size"].Value)
+	assert.Equal(t, int64(proto.Size(pingResponse)), entryCtx["grpc_max_sent_size"].Value)
+	assert.Contains(t, entryCtx, "grpc_first_message_latency")
+	assert.Contains(t, entryCtx, "grpc_longest_message_gap")
+}
+
+func TestStreamInterceptor_WithMinLevel(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(server_interceptor.StreamInterceptor(myLogger, logger_grpc.WithMinLevel(logger.InfoLevel))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	assert.NoError(t, resp.Send(pingRequest))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	assert.NoError(t, resp.CloseSend())
+
+	assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 1 }, time.Second, time.Millisecond)
+	entries := myLogger.GetEntries()
+
+	entry := entries[0]
+	entryCtx := *entry.Context
+	assert.Equal(t, logger.InfoLevel, entry.Level)
+	assert.Regexp(t, `grpc server stream call /mwitkow\.testproto\.TestService/PingStream \[code:OK, duration:.*\]`, entry.Message)
+	assert.Equal(t, int64(1), entryCtx["grpc_received_messages"].Value)
+	assert.Equal(t, int64(1), entryCtx["grpc_sent_messages"].Value)
+	assert.NotContains(t, entryCtx, "grpc_recv_data")
+}
+
+func TestStreamInterceptor_WithStreamHeartbeat(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(server_interceptor.StreamInterceptor(myLogger, logger_grpc.WithStreamHeartbeat(20*time.Millisecond))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "my_fake_ping_payload"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	heartbeatEntries := func() (heartbeats []logger.Entry) {
+		for _, e := range myLogger.GetEntries() {
+			if strings.HasPrefix(e.Message, "grpc server stream still active /mwitkow.testproto.TestService/PingStream") {
+				heartbeats = append(heartbeats, e)
+			}
+		}
+		return heartbeats
+	}
+	assert.Eventually(t, func() bool { return len(heartbeatEntries()) >= 2 }, time.Second, time.Millisecond)
+	assert.NoError(t, resp.CloseSend())
+
+	assert.Eventually(t, func() bool {
+		entries := myLogger.GetEntries()
+		return len(entries) > 0 && strings.HasPrefix(entries[len(entries)-1].Message, "grpc server stream call")
+	}, time.Second, time.Millisecond, "time until all request over")
+	for _, e := range heartbeatEntries() {
+		eCtx := *e.Context
+		assert.Equal(t, logger.InfoLevel, e.Level)
+		assert.Contains(t, eCtx, "grpc_stream_elapsed")
+		assert.Contains(t, eCtx, "grpc_stream_idle")
+		assert.Contains(t, eCtx, "grpc_heartbeat_sent_messages")
+		assert.Contains(t, eCtx, "grpc_heartbeat_received_messages")
+	}
+
+	entries := len(myLogger.GetEntries())
+	assert.Never(t, func() bool { return len(myLogger.GetEntries()) != entries }, 50*time.Millisecond, time.Millisecond, "heartbeat must stop with the stream")
+}
+
+type slowSendStream struct {
+	grpc.ServerStream
+	delay time.Duration
+}
+
+func (s *slowSendStream) SendMsg(m interface{}) error {
+	time.Sleep(s.delay)
+	return s.ServerStream.SendMsg(m)
+}
+
+func TestStreamInterceptor_WithStreamIdleTimeout(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(server_interceptor.StreamInterceptor(myLogger, logger_grpc.WithStreamIdleTimeout(20*time.Millisecond))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "my_fake_ping_payload"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	idleEntries := func() (idle []logger.Entry) {
+		for _, e := range myLogger.GetEntries() {
+			if strings.HasPrefix(e.Message, "grpc server stream idle /mwitkow.testproto.TestService/PingStream") {
+				idle = append(idle, e)
+			}
+		}
+		return idle
+	}
+	assert.Eventually(t, func() bool { return len(idleEntries()) == 1 }, time.Second, time.Millisecond)
+	assert.Never(t, func() bool { return len(idleEntries()) > 1 }, 50*time.Millisecond, time.Millisecond, "idle warning is logged once per idle period")
+	assert.NoError(t, resp.CloseSend())
+
+	assert.Eventually(t, func() bool {
+		entries := myLogger.GetEntries()
+		return len(entries) > 0 && strings.HasPrefix(entries[len(entries)-1].Message, "grpc server stream call")
+	}, time.Second, time.Millisecond, "time until all request over")
+	assert.Len(t, idleEntries(), 1)
+
+	entry := idleEntries()[0]
+	entryCtx := *entry.Context
+	assert.Equal(t, logger.WarningLevel, entry.Level)
+	assert.Contains(t, entryCtx, "grpc_stream_idle")
+	assert.Contains(t, entryCtx, "grpc_stream_send_idle")
+	assert.Contains(t, entryCtx, "grpc_stream_receive_idle")
+}
+
+func TestStreamInterceptor_WithTinyStreamIdleTimeout(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(server_interceptor.StreamInterceptor(myLogger, logger_grpc.WithStreamIdleTimeout(3))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+	defer its.TearDownSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	// a timeout under 4ns must not give the idle detection ticker a zero interval
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "my_fake_ping_payload"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	assert.NoError(t, resp.CloseSend())
+
+	assert.Eventually(t, func() bool {
+		entries := myLogger.GetEntries()
+		return len(entries) > 0 && strings.HasPrefix(entries[len(entries)-1].Message, "grpc server stream call")
+	}, time.Second, time.Millisecond, "time until all request over")
+}
+
+func TestStreamInterceptor_WithSendStallThreshold(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
+				func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+					return handler(srv, &slowSendStream{ServerStream: stream, delay: 30 * time.Millisecond})
+				},
+				server_interceptor.StreamInterceptor(myLogger, logger_grpc.WithSendStallThreshold(10*time.Millisecond)),
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "my_fake_ping_payload"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	assert.NoError(t, resp.CloseSend())
+
+	assert.Eventually(t, func() bool {
+		entries := myLogger.GetEntries()
+		return len(entries) > 0 && strings.HasPrefix(entries[len(entries)-1].Message, "grpc server stream call")
+	}, time.Second, time.Millisecond, "time until all request over")
+	var stallEntries []logger.Entry
+	for _, e := range myLogger.GetEntries() {
+		if strings.HasPrefix(e.Message, "grpc server stream send stalled /mwitkow.testproto.TestService/PingStream") {
+			stallEntries = append(stallEntries, e)
+		}
+	}
+	assert.Len(t, stallEntries, 1)
+
+	entry := stallEntries[0]
+	entryCtx := *entry.Context
+	assert.Equal(t, logger.WarningLevel, entry.Level)
+	assert.Equal(t, "send", entryCtx["grpc_stall_direction"].Value)
+	assert.Contains(t, entryCtx, "grpc_stall_duration")
+}
+
+func TestStreamInterceptor_WithFieldExtractors(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(server_interceptor.StreamInterceptor(myLogger, logger_grpc.WithFieldExtractors(
+				logger_grpc.ProtoFields(map[string]string{"value": "ping_value"}),
+			))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "first_ping"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "second_ping"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	assert.NoError(t, resp.CloseSend())
+
+	assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 7 }, time.Second, time.Millisecond)
+	entries := myLogger.GetEntries()
+
+	for _, e := range entries[1:] {
+		assert.Equal(t, "first_ping", (*e.Context)["ping_value"].Value)
+	}
+}
+
+func TestStreamInterceptor_WithPayloadLimits(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(server_interceptor.StreamInterceptor(myLogger, logger_grpc.WithPayloadLimits(logger_grpc.PayloadLimits{
+				MaxStringLength: 7,
+			}))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	assert.NoError(t, resp.Send(pingRequest))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	assert.NoError(t, resp.CloseSend())
+
+	assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 5 }, time.Second, time.Millisecond)
+	entries := myLogger.GetEntries()
+
+	recvCtx := *entries[1].Context
+	assert.Equal(t, map[string]interface{}{"value": "my_fake" + logger_grpc.TruncationMarker}, recvCtx["grpc_recv_data"].Value)
+	assert.Equal(t, true, recvCtx["grpc_recv_data_truncated"].Value)
+	assert.Equal(t, int64(proto.Size(pingRequest)), recvCtx["grpc_recv_data_size"].Value)
+
+	sendCtx := *entries[2].Context
+	assert.Equal(t, map[string]interface{}{"Value": "my_fake" + logger_grpc.TruncationMarker}, sendCtx["grpc_send_data"].Value)
+	assert.Equal(t, true, sendCtx["grpc_send_data_truncated"].Value)
+}
+
+func TestStreamInterceptor_WithPayloadLimitsOnCharacterBoundary(t *testing.T) {
+	tests := []struct {
+		name             string
+		limits           logger_grpc.PayloadLimits
+		expectedRecvData interface{}
+	}{
+		{
+			name:             "string length",
+			limits:           logger_grpc.PayloadLimits{MaxStringLength: 4},
+			expectedRecvData: map[string]interface{}{"value": "ét" + logger_grpc.TruncationMarker},
+		},
+		{
+			name:             "serialized size",
+			limits:           logger_grpc.PayloadLimits{MaxSize: 11},
+			expectedRecvData: `{"value":"` + logger_grpc.TruncationMarker,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			myLogger := &testing_logger.Logger{}
+
+			its := &grpc_testing.InterceptorTestSuite{
+				ServerOpts: []grpc.ServerOption{
+					grpc.StreamInterceptor(server_interceptor.StreamInterceptor(myLogger, logger_grpc.WithPayloadLimits(tt.limits))),
+				},
+			}
+			its.Suite.SetT(t)
+			its.SetupSuite()
+			defer its.TearDownSuite()
+
+			c := its.NewClient()
+
+			resp, err := c.PingStream(its.SimpleCtx())
+			assert.NoError(t, err)
+
+			// the limits fall inside the 2 bytes "é" characters
+			assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "été"}))
+			_, err = resp.Recv()
+			assert.NoError(t, err)
+			assert.NoError(t, resp.CloseSend())
+
+			assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 5 }, time.Second, time.Millisecond)
+			recvCtx := *myLogger.GetEntries()[1].Context
+			assert.Equal(t, tt.expectedRecvData, recvCtx["grpc_recv_data"].Value)
+			assert.Equal(t, true, recvCtx["grpc_recv_data_truncated"].Value)
+		})
+	}
+}
+
+func TestStreamInterceptor_WithAsyncLogger(t *testing.T) {
+	var mu sync.Mutex
+	var entries []logger.Entry
+	release := make(chan struct{})
+	// the slow handler waits for the stream to be over so the queue fills up
+	slowLogger := logger.NewLogger(func(entry logger.Entry) error {
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, entry)
+		return nil
+	})
+	asyncLogger := logger_grpc.NewAsyncLogger(slowLogger, logger_grpc.WithAsyncQueueSize(2), logger_grpc.WithAsyncDropReportInterval(0))
+
+	// handled is closed once the debug entries are logged, done once the call entry is logged
+	handled := make(chan struct{})
+	done := make(chan struct{})
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
+				func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+					defer close(done)
+					return handler(srv, stream)
+				},
+				server_interceptor.StreamInterceptor(asyncLogger),
+				func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+					defer close(handled)
+					return handler(srv, stream)
+				},
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+
+	resp, err := c.PingStream(its.SimpleCtx())
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Send(&pb_testproto.PingRequest{Value: "my_fake_ping_payload"}))
+	_, err = resp.Recv()
+	assert.NoError(t, err)
+	assert.NoError(t, resp.CloseSend())
+
+	<-handled
+	close(release)
+	<-done
+	assert.NoError(t, asyncLogger.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	report := entries[len(entries)-1]
+	assert.Equal(t, logger.WarningLevel, report.Level)
+	dropped := (*report.Context)["log_dropped"].Value.(int64)
+	assert.True(t, dropped > 0)
+	assert.Equal(t, dropped, (*report.Context)["log_dropped_debug"].Value)
+	assert.Equal(t, fmt.Sprintf("async logger dropped %d entries", dropped), report.Message)
+	// the begin, receive, send and EOF debug entries are dropped first, the call entry is always handled
+	assert.Equal(t, int64(5), int64(len(entries)-1)+dropped)
+	call := entries[len(entries)-2]
+	assert.Equal(t, logger.InfoLevel, call.Level)
+	assert.Equal(t, "OK", (*call.Context)["grpc_code"].Value)
+}
@@ -3,11 +3,13 @@ package server_interceptor
 import (
 	"context"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/gol4ng/logger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
 	logger_grpc "github.com/gol4ng/logger-grpc"
 )
@@ -18,21 +20,94 @@ type StreamWrapper struct {
 	context       context.Context
 	logger        logger.LoggerInterface
 	loggerContext *logger.Context
+	metadata      *metadataRecorder
+	hooks         logger_grpc.Hooks
+	mu            sync.Mutex
+	info          *logger_grpc.CallInfo
+	// snapshotFunc is the snapshot method value, it is bound once instead of on each send
+	snapshotFunc func() logger_grpc.CallInfo
 }
 
 func (s *StreamWrapper) getLoggerContext() *logger.Context {
-	return (&logger.Context{}).Merge(*s.loggerContext)
+	return s.options.AcquireContext().Merge(*s.loggerContext)
+}
+
+func (s *StreamWrapper) event(eventType logger_grpc.EventType, duration time.Duration) *logger_grpc.CallEvent {
+	return &logger_grpc.CallEvent{Type: eventType, Kind: "server", CallType: "stream", FullMethod: s.info.FullMethod, Duration: duration}
+}
+
+func (s *StreamWrapper) log(event *logger_grpc.CallEvent, level logger.Level, ctx *logger.Context) {
+	_ = s.logger.Log(s.options.FormatMessage(event), level, s.options.ApplySchema(ctx))
+	s.options.ReleaseContext(ctx)
+}
+
+func (s *StreamWrapper) logError(event *logger_grpc.CallEvent, ctx *logger.Context, err error) {
+	code, level := s.options.ErrorLevel(s.snapshot(), err)
+	if !s.options.Enabled(level) {
+		s.options.ReleaseContext(ctx)
+		return
+	}
+	event.Code = code
+	event.Err = err
+	s.log(event, level, logger_grpc.FeedStatusDetails(ctx.Add("grpc_error", err).Add("grpc_code", code.String()), err, s.options))
+}
+
+// onMessage records the message in the call info and calls the hooks with a snapshot of the call info,
+// the snapshot is only taken when the message is dispatched to the hooks
+func (s *StreamWrapper) onMessage(message logger_grpc.MessageInfo) {
+	s.mu.Lock()
+	if message.Type == logger_grpc.EventReceive && s.info.ReceivedMessages == 0 {
+		s.info.RequestFields = s.options.ExtractRequestFields(message.Message)
+	}
+	s.info.RecordMessage(&message)
+	if !s.options.DispatchMessage(message.Type) {
+		s.mu.Unlock()
+		return
+	}
+	info := *s.info
+	s.mu.Unlock()
+	s.dispatchMessage(info, message)
+}
+
+// dispatchMessage calls the hooks, the call info and message copies are only allocated when they are dispatched
+func (s *StreamWrapper) dispatchMessage(info logger_grpc.CallInfo, message logger_grpc.MessageInfo) {
+	s.hooks.OnMessage(s.context, &info, &message)
+}
+
+// snapshot returns a copy of the current call info
+func (s *StreamWrapper) snapshot() logger_grpc.CallInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.info
+}
+
+func (s *StreamWrapper) SetHeader(md metadata.MD) error {
+	err := s.ServerStream.SetHeader(md)
+	if err == nil {
+		s.metadata.addHeader(md)
+	}
+	return err
+}
+
+func (s *StreamWrapper) SetTrailer(md metadata.MD) {
+	s.ServerStream.SetTrailer(md)
+	s.metadata.addTrailer(md)
+}
+
+func (s *StreamWrapper) headerContext(md metadata.MD) *logger.Context {
+	return s.getLoggerContext().Add("grpc_metadata", logger_grpc.RedactMetadata(md, s.options))
 }
 
 func (s *StreamWrapper) SendHeader(md metadata.MD) error {
 	err := s.ServerStream.SendHeader(md)
-	ctx := s.getLoggerContext().Add("grpc_metadata", md)
 	if err != nil {
-		code := s.options.CodeFunc(err)
-		_ = s.logger.Log("grpc server stream send header error", s.options.LevelFunc(code), ctx.Add("grpc_error", err).Add("grpc_code", code.String()))
+		s.logError(s.event(logger_grpc.EventSendHeaderError, 0), s.headerContext(md), err)
 		return err
 	}
-	_ = s.logger.Debug("grpc server stream send header", ctx)
+	s.metadata.addHeader(md)
+	if s.options.Enabled(logger.DebugLevel) {
+		s.log(s.event(logger_grpc.EventSendHeader, 0), logger.DebugLevel, s.headerContext(md))
+	}
 	return err
 }
 
@@ -42,41 +117,48 @@ func (s *StreamWrapper) Context() context.Context {
 
 func (s *StreamWrapper) SendMsg(m interface{}) error {
 	startTime := time.Now()
+	sendDone := logger_grpc.WatchSendStall(s.logger, s.loggerContext, s.options, s.snapshotFunc)
 	err := s.ServerStream.SendMsg(m)
-	ctx := s.getLoggerContext().Add("grpc_send_data", m).Add("grpc_duration", time.Since(startTime).Seconds())
+	sendDone()
+	eventType := logger_grpc.EventSend
 	if err != nil {
-		code := s.options.CodeFunc(err)
-		_ = s.logger.Log("grpc server stream send error", s.options.LevelFunc(code), ctx.Add("grpc_error", err).Add("grpc_code", code.String()))
-		return err
+		eventType = logger_grpc.EventSendError
 	}
-	_ = s.logger.Debug("grpc server stream send message", ctx)
+	s.onMessage(logger_grpc.NewMessageInfo(eventType, m, time.Since(startTime), err))
 	return err
 }
 
 func (s *StreamWrapper) RecvMsg(m interface{}) error {
 	startTime := time.Now()
 	err := s.ServerStream.RecvMsg(m)
-	ctx := s.getLoggerContext().Add("grpc_duration", time.Since(startTime).Seconds())
-	if err == io.EOF {
-		_ = s.logger.Debug("grpc server stream receive EOF", ctx)
-		return err
+	eventType := logger_grpc.EventReceive
+	switch {
+	case err == io.EOF:
+		eventType = logger_grpc.EventReceiveEOF
+	case err != nil:
+		eventType = logger_grpc.EventReceiveError
 	}
-	ctx.Add("grpc_recv_data", m)
-	if err != nil {
-		code := s.options.CodeFunc(err)
-		_ = s.logger.Log("grpc server stream receive error", s.options.LevelFunc(code), ctx.Add("grpc_error", err).Add("grpc_code", code.String()))
-		return err
-	}
-	_ = s.logger.Debug("grpc server stream receive message", ctx)
+	s.onMessage(logger_grpc.NewMessageInfo(eventType, m, time.Since(startTime), err))
 	return err
 }
 
 func NewServerStreamWrapper(stream grpc.ServerStream, context context.Context, options *logger_grpc.Options, l logger.LoggerInterface, loggerContext *logger.Context) *StreamWrapper {
-	return &StreamWrapper{
+	recorder := &metadataRecorder{}
+	fullMethod, _ := grpc.MethodFromServerStream(stream)
+	info := &logger_grpc.CallInfo{FullMethod: fullMethod, Kind: "server", CallType: "stream", StartTime: time.Now()}
+	if p, ok := peer.FromContext(context); ok {
+		info.Peer = p.Addr
+	}
+	wrapper := &StreamWrapper{
 		ServerStream:  stream,
-		context:       context,
+		context:       withMetadataRecorder(context, recorder),
 		options:       options,
 		logger:        l,
 		loggerContext: loggerContext,
+		metadata:      recorder,
+		hooks:         logger_grpc.CallHooks(l, loggerContext, options),
+		info:          info,
 	}
+	wrapper.snapshotFunc = wrapper.snapshot
+	return wrapper
 }
@@ -0,0 +1,58 @@
+package server_interceptor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gol4ng/logger"
+	"github.com/gol4ng/logger/middleware"
+	pb_testproto "github.com/grpc-ecosystem/go-grpc-middleware/testing/testproto"
+	"google.golang.org/grpc"
+
+	logger_grpc "github.com/gol4ng/logger-grpc"
+	"github.com/gol4ng/logger-grpc/server_interceptor"
+)
+
+type benchmarkServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *benchmarkServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *benchmarkServerStream) SendMsg(interface{}) error {
+	return nil
+}
+
+func (s *benchmarkServerStream) RecvMsg(interface{}) error {
+	return nil
+}
+
+func benchmarkStreamWrapper(b *testing.B, opts ...logger_grpc.Option) {
+	myLogger := logger.NewLogger(middleware.MinLevelFilter(logger.InfoLevel)(logger.NopHandler))
+	ctx := context.Background()
+	o := logger_grpc.EvaluateServerOpt(opts)
+	loggerContext := logger_grpc.FeedCallContext(nil, ctx, "/mwitkow.testproto.TestService/PingStream", time.Now(), o)
+	stream := server_interceptor.NewServerStreamWrapper(&benchmarkServerStream{ctx: ctx}, ctx, o, myLogger, loggerContext)
+	pingResponse := &pb_testproto.PingResponse{Value: "my_fake_ping_payload", Counter: 42}
+	pingRequest := &pb_testproto.PingRequest{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = stream.SendMsg(pingResponse)
+		_ = stream.RecvMsg(pingRequest)
+	}
+}
+
+func BenchmarkStreamWrapper_DebugDisabled(b *testing.B) {
+	b.Run("without level gate", func(b *testing.B) {
+		benchmarkStreamWrapper(b)
+	})
+	b.Run("with min level", func(b *testing.B) {
+		benchmarkStreamWrapper(b, logger_grpc.WithMinLevel(logger.InfoLevel))
+	})
+}
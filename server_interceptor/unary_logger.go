@@ -2,45 +2,57 @@ package server_interceptor
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/gol4ng/logger"
 	logger_grpc "github.com/gol4ng/logger-grpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 )
 
+// kindField is the precomputed grpc_kind field of the server calls
+var kindField = logger.String("server")
+
 // UnaryInterceptor returns a new unary server interceptors that log.
 func UnaryInterceptor(log logger.LoggerInterface, opts ...logger_grpc.Option) grpc.UnaryServerInterceptor {
 	o := logger_grpc.EvaluateServerOpt(opts)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		startTime := time.Now()
 
+		callInfo := &logger_grpc.CallInfo{FullMethod: info.FullMethod, Kind: "server", CallType: "unary", StartTime: startTime, Request: req}
+		if p, ok := peer.FromContext(ctx); ok {
+			callInfo.Peer = p.Addr
+		}
 		currentLogger := logger.FromContext(ctx, log)
-		currentLoggerContext := logger_grpc.FeedContext(o.LoggerContextProvider(info.FullMethod), ctx, info.FullMethod, startTime).Add("grpc_kind", "server")
+		currentLoggerContext := o.CallContextProvider(ctx, callInfo, req)
+		if currentLoggerContext == nil {
+			currentLoggerContext = o.AcquireContext()
+			defer o.ReleaseContext(currentLoggerContext)
+		}
+		logger_grpc.FeedCallContext(currentLoggerContext, ctx, info.FullMethod, startTime, o).Set("grpc_kind", kindField)
+		hooks := logger_grpc.CallHooks(currentLogger, currentLoggerContext, o)
+		recorder := &metadataRecorder{}
 
 		defer func() {
-			duration := time.Since(startTime)
-			currentLoggerContext.Add("grpc_duration", duration.Seconds())
+			callInfo.Duration = time.Since(startTime)
+			callInfo.Header, callInfo.Trailer = recorder.metadata()
 
 			if err := recover(); err != nil {
-				currentLoggerContext.Add("grpc_panic", err)
-				_ = currentLogger.Critical(fmt.Sprintf("grpc server unary panic %s [duration:%s]", info.FullMethod, duration), currentLoggerContext)
+				callInfo.Panic = err
+				hooks.OnFinish(ctx, callInfo)
 				panic(err)
 			}
 
-			code := o.CodeFunc(err)
-			codeStr := code.String()
-			currentLoggerContext.Add("grpc_code", codeStr)
-			if err != nil {
-				currentLoggerContext.
-					Add("grpc_error", err).
-					Add("grpc_error_message", err.Error())
+			callInfo.Code = o.CodeFunc(err)
+			callInfo.Err = err
+			if err == nil {
+				callInfo.Response = resp
 			}
-
-			_ = currentLogger.Log(fmt.Sprintf("grpc server unary call %s [code:%s, duration:%s]", info.FullMethod, codeStr, duration), o.LevelFunc(code), currentLoggerContext)
+			hooks.OnFinish(ctx, callInfo)
 		}()
 
-		return handler(ctx, req)
+		callInfo.RequestFields = o.ExtractRequestFields(req)
+		hooks.OnStart(ctx, callInfo)
+		return handler(withMetadataRecorder(ctx, recorder), req)
 	}
 }
@@ -1,15 +1,28 @@
 package server_interceptor_test
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gol4ng/logger"
 	testing_logger "github.com/gol4ng/logger/testing"
+	"github.com/golang/protobuf/proto"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpc_testing "github.com/grpc-ecosystem/go-grpc-middleware/testing"
 	pb_testproto "github.com/grpc-ecosystem/go-grpc-middleware/testing/testproto"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	logger_grpc "github.com/gol4ng/logger-grpc"
 	"github.com/gol4ng/logger-grpc/server_interceptor"
@@ -47,6 +60,42 @@ func TestUnaryInterceptor(t *testing.T) {
 	assert.Contains(t, *entry.Context, "grpc_start_time")
 	assert.Contains(t, *entry.Context, "grpc_request_deadline")
 	assert.Contains(t, *entry.Context, "grpc_duration")
+	assert.InDelta(t, 2, (*entry.Context)["grpc_deadline_budget"].Value, 0.1)
+	assert.Contains(t, *entry.Context, "grpc_deadline_budget_used")
+	assert.NotContains(t, *entry.Context, "grpc_deadline_near")
+}
+
+func TestUnaryInterceptor_WithDeadlineWarning(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+				server_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithDeadlineWarning(0.9)),
+				func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+					time.Sleep(30 * time.Millisecond)
+					return handler(ctx, req)
+				},
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := c.Ping(ctx, &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, logger.WarningLevel, entry.Level)
+	assert.Equal(t, "OK", (*entry.Context)["grpc_code"].Value)
+	assert.Equal(t, true, (*entry.Context)["grpc_deadline_near"].Value)
+	assert.Greater(t, (*entry.Context)["grpc_deadline_budget_used"].Value, 0.1)
 }
 
 func TestUnaryInterceptor_WithContext(t *testing.T) {
@@ -121,3 +170,780 @@ func TestUnaryInterceptor_WithLevel(t *testing.T) {
 	assert.Contains(t, *entry.Context, "grpc_request_deadline")
 	assert.Contains(t, *entry.Context, "grpc_duration")
 }
+
+func TestUnaryInterceptor_WithMetadata(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+				server_interceptor.UnaryInterceptor(myLogger,
+					logger_grpc.WithMetadata(logger_grpc.AllMetadata),
+					logger_grpc.WithMetadataRedactor(logger_grpc.RedactMetadataKeys("X-Secret")),
+				),
+				func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+					_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", "my_request_id", "x-secret", "my_secret"))
+					_ = grpc.SetTrailer(ctx, metadata.Pairs("x-ratelimit-remaining", "41"))
+					return handler(ctx, req)
+				},
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	header := metadata.MD{}
+	resp, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}, grpc.Header(&header))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "my_fake_ping_payload", resp.Value)
+	assert.Equal(t, []string{"my_secret"}, header.Get("x-secret"))
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, "my_request_id", (*entry.Context)["grpc_header_x-request-id"].Value)
+	assert.Equal(t, "[REDACTED]", (*entry.Context)["grpc_header_x-secret"].Value)
+	assert.Equal(t, "41", (*entry.Context)["grpc_trailer_x-ratelimit-remaining"].Value)
+}
+
+func TestUnaryInterceptor_WithStatusDetails(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+				server_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithStatusDetailRenderers(func(loggerContext *logger.Context, detail interface{}) bool {
+					if help, ok := detail.(*errdetails.Help); ok {
+						loggerContext.Add("grpc_error_help_url", help.GetLinks()[0].GetUrl())
+						return true
+					}
+					return false
+				})),
+				func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+					st, _ := status.New(codes.InvalidArgument, "invalid ping").WithDetails(
+						&errdetails.ErrorInfo{Reason: "PING_EMPTY", Domain: "ping.example.com", Metadata: map[string]string{"field": "value"}},
+						&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "value", Description: "must not be empty"}}},
+						&errdetails.DebugInfo{Detail: "my_debug_detail"},
+						&errdetails.Help{Links: []*errdetails.Help_Link{{Url: "http://my_help_url"}}},
+						&errdetails.LocalizedMessage{Locale: "fr-FR", Message: "ping invalide"},
+					)
+					return nil, st.Err()
+				},
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, logger.NoticeLevel, entry.Level)
+	assert.Equal(t, "InvalidArgument", (*entry.Context)["grpc_code"].Value)
+	assert.Equal(t, "PING_EMPTY", (*entry.Context)["grpc_error_reason"].Value)
+	assert.Equal(t, "ping.example.com", (*entry.Context)["grpc_error_domain"].Value)
+	assert.Equal(t, map[string]string{"field": "value"}, (*entry.Context)["grpc_error_metadata"].Value)
+	assert.Equal(t, []map[string]string{{"field": "value", "description": "must not be empty"}}, (*entry.Context)["grpc_error_field_violations"].Value)
+	assert.Equal(t, "my_debug_detail", (*entry.Context)["grpc_error_debug_detail"].Value)
+	assert.Equal(t, "http://my_help_url", (*entry.Context)["grpc_error_help_url"].Value)
+
+	details := (*entry.Context)["grpc_error_details"].Value.([]interface{})
+	assert.Len(t, details, 1)
+	assert.Equal(t, "ping invalide", details[0].(*errdetails.LocalizedMessage).GetMessage())
+}
+
+func TestUnaryInterceptor_WithCancelCause(t *testing.T) {
+	shutdownCtx, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+	tests := []struct {
+		name          string
+		callTimeout   time.Duration
+		shutdown      bool
+		handlerErr    func(ctx context.Context) error
+		expectedCause string
+		expectedLevel logger.Level
+	}{
+		{
+			name:        "client deadline",
+			callTimeout: 20 * time.Millisecond,
+			handlerErr: func(ctx context.Context) error {
+				<-ctx.Done()
+				return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+			},
+			expectedCause: "client_deadline",
+			expectedLevel: logger.WarningLevel,
+		},
+		{
+			name:        "server timeout",
+			callTimeout: time.Second,
+			handlerErr: func(ctx context.Context) error {
+				return status.Error(codes.DeadlineExceeded, "database timeout")
+			},
+			expectedCause: "server_timeout",
+			expectedLevel: logger.WarningLevel,
+		},
+		{
+			name:        "server shutdown",
+			callTimeout: time.Second,
+			shutdown:    true,
+			handlerErr: func(ctx context.Context) error {
+				return status.Error(codes.Canceled, "shutting down")
+			},
+			expectedCause: "server_shutdown",
+			expectedLevel: logger.EmergencyLevel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			myLogger := &testing_logger.Logger{}
+
+			its := &grpc_testing.InterceptorTestSuite{
+				ServerOpts: []grpc.ServerOption{
+					grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+						server_interceptor.UnaryInterceptor(myLogger,
+							logger_grpc.WithShutdownContext(shutdownCtx),
+							logger_grpc.WithCancelCauseLevels(func(cause logger_grpc.CancelCause, level logger.Level) logger.Level {
+								if cause == logger_grpc.CancelCauseServerShutdown {
+									return logger.EmergencyLevel
+								}
+								return level
+							}),
+						),
+						func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+							return nil, tt.handlerErr(ctx)
+						},
+					)),
+				},
+			}
+			its.Suite.SetT(t)
+			its.SetupSuite()
+
+			if tt.shutdown {
+				shutdown()
+			}
+			c := its.NewClient()
+			ctx, cancel := context.WithTimeout(context.Background(), tt.callTimeout)
+			defer cancel()
+			_, err := c.Ping(ctx, &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+			assert.Error(t, err)
+
+			assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 1 }, time.Second, time.Millisecond)
+			entries := myLogger.GetEntries()
+
+			entry := entries[0]
+			assert.Equal(t, tt.expectedLevel, entry.Level)
+			assert.Equal(t, tt.expectedCause, (*entry.Context)["grpc_cancel_cause"].Value)
+		})
+	}
+}
+
+func TestUnaryInterceptor_WithClientDeadlineGrace(t *testing.T) {
+	tests := []struct {
+		name          string
+		grace         time.Duration
+		expectedCause string
+	}{
+		{name: "deadline within the grace", grace: 2 * time.Minute, expectedCause: "client_deadline"},
+		{name: "deadline beyond the grace", grace: 30 * time.Second, expectedCause: "client_canceled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			myLogger := &testing_logger.Logger{}
+			started := make(chan struct{})
+
+			its := &grpc_testing.InterceptorTestSuite{
+				ServerOpts: []grpc.ServerOption{
+					grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+						server_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithClientDeadlineGrace(tt.grace)),
+						func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+							close(started)
+							<-ctx.Done()
+							return nil, status.Error(codes.Canceled, ctx.Err().Error())
+						},
+					)),
+				},
+			}
+			its.Suite.SetT(t)
+			its.SetupSuite()
+			defer its.TearDownSuite()
+
+			c := its.NewClient()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			go func() {
+				<-started
+				cancel()
+			}()
+			_, err := c.Ping(ctx, &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+			assert.Equal(t, codes.Canceled, status.Code(err))
+
+			assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 1 }, time.Second, time.Millisecond)
+			entries := myLogger.GetEntries()
+			assert.Equal(t, tt.expectedCause, (*entries[0].Context)["grpc_cancel_cause"].Value)
+		})
+	}
+}
+
+func TestUnaryInterceptor_WithFormats(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger,
+				logger_grpc.WithTimeLayout(time.RFC3339Nano),
+				logger_grpc.WithTimeLocation(time.UTC),
+				logger_grpc.WithDurationFormatter(logger_grpc.DurationNanoseconds),
+				logger_grpc.WithMessageDuration(false),
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, "grpc server unary call /mwitkow.testproto.TestService/Ping [code:OK]", entry.Message)
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d+Z$`, (*entry.Context)["grpc_start_time"].Value)
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d+Z$`, (*entry.Context)["grpc_request_deadline"].Value)
+	assert.IsType(t, int64(0), (*entry.Context)["grpc_duration"].Value)
+	assert.IsType(t, int64(0), (*entry.Context)["grpc_deadline_budget"].Value)
+}
+
+func TestUnaryInterceptor_WithFieldSchema(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger,
+				logger_grpc.WithFieldSchema(logger_grpc.OTelFieldSchema),
+				logger_grpc.WithFieldNames(map[string]string{"grpc_kind": "rpc.grpc.kind"}),
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entryCtx := *entries[0].Context
+	assert.Equal(t, "mwitkow.testproto.TestService", entryCtx["rpc.service"].Value)
+	assert.Equal(t, "Ping", entryCtx["rpc.method"].Value)
+	assert.Equal(t, int64(0), entryCtx["rpc.grpc.status_code"].Value)
+	assert.Equal(t, "grpc", entryCtx["rpc.system"].Value)
+	assert.Equal(t, "server", entryCtx["rpc.grpc.kind"].Value)
+	assert.Contains(t, entryCtx, "grpc_duration")
+	assert.NotContains(t, entryCtx, "grpc_service")
+	assert.NotContains(t, entryCtx, "grpc_method")
+	assert.NotContains(t, entryCtx, "grpc_code")
+	assert.NotContains(t, entryCtx, "grpc_kind")
+}
+
+func TestUnaryInterceptor_WithECSFieldSchema(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+				grpc_recovery.UnaryServerInterceptor(),
+				server_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithFieldSchema(logger_grpc.ECSFieldSchema)),
+				func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+					if req.(*pb_testproto.PingRequest).Value == "panic" {
+						panic("my_panic")
+					}
+					return nil, status.Error(codes.InvalidArgument, "invalid ping")
+				},
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+	defer its.TearDownSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "panic"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 2)
+
+	errorCtx := *entries[0].Context
+	assert.Equal(t, "rpc error: code = InvalidArgument desc = invalid ping", errorCtx["error.message"].Value)
+	assert.Equal(t, "InvalidArgument", errorCtx["error.type"].Value)
+	assert.Equal(t, "InvalidArgument", errorCtx["grpc.code"].Value)
+	assert.NotContains(t, errorCtx, "grpc_error")
+	assert.NotContains(t, errorCtx, "grpc_error_message")
+
+	panicCtx := *entries[1].Context
+	assert.Equal(t, logger.CriticalLevel, entries[1].Level)
+	assert.Equal(t, "my_panic", panicCtx["error.message"].Value)
+	assert.Equal(t, "panic", panicCtx["error.type"].Value)
+	assert.NotContains(t, panicCtx, "grpc_panic")
+}
+
+func TestUnaryInterceptor_WithMessageFormatter(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger,
+				logger_grpc.WithMessageFormatter(func(event *logger_grpc.CallEvent) string {
+					return fmt.Sprintf("%s %s %s %s", event.Type, event.Kind, event.FullMethod, event.Code)
+				}),
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+	_, err = c.PingError(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload", ErrorCodeReturned: uint32(codes.NotFound)})
+	assert.Error(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "call server /mwitkow.testproto.TestService/Ping OK", entries[0].Message)
+	assert.Equal(t, "call server /mwitkow.testproto.TestService/PingError NotFound", entries[1].Message)
+}
+
+func TestUnaryInterceptor_WithSlowCalls(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+				server_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithSlowCalls(logger_grpc.SlowCallThresholds(time.Hour, time.Hour, map[string]time.Duration{
+					"/mwitkow.testproto.TestService/Ping": 10 * time.Millisecond,
+				}))),
+				func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+					time.Sleep(20 * time.Millisecond)
+					return handler(ctx, req)
+				},
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+	_, err = c.PingEmpty(its.SimpleCtx(), &pb_testproto.Empty{})
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 2)
+
+	assert.Equal(t, logger.WarningLevel, entries[0].Level)
+	assert.Equal(t, true, (*entries[0].Context)["grpc_slow"].Value)
+	assert.Equal(t, logger.InfoLevel, entries[1].Level)
+	assert.Equal(t, false, (*entries[1].Context)["grpc_slow"].Value)
+}
+
+func TestUnaryInterceptor_WithCallLevels(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger,
+				logger_grpc.WithLevels(func(code codes.Code) logger.Level {
+					return logger.EmergencyLevel
+				}),
+				logger_grpc.WithCallLevels(func(info *logger_grpc.CallInfo) logger.Level {
+					if info.FullMethod == "/mwitkow.testproto.TestService/PingEmpty" {
+						return logger.DebugLevel
+					}
+					if info.Kind == "server" && info.Code == codes.NotFound && info.Err != nil && info.Duration > 0 {
+						return logger.NoticeLevel
+					}
+					return logger.InfoLevel
+				}),
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.PingEmpty(its.SimpleCtx(), &pb_testproto.Empty{})
+	assert.NoError(t, err)
+	_, err = c.PingError(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload", ErrorCodeReturned: uint32(codes.NotFound)})
+	assert.Error(t, err)
+	_, err = c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 3)
+	assert.Equal(t, logger.DebugLevel, entries[0].Level)
+	assert.Equal(t, logger.NoticeLevel, entries[1].Level)
+	assert.Equal(t, logger.InfoLevel, entries[2].Level)
+}
+
+func TestUnaryInterceptor_WithCallContext(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithCallContext(func(ctx context.Context, info *logger_grpc.CallInfo, req interface{}) *logger.Context {
+				loggerContext := logger.NewContext().Add("full_method", info.FullMethod)
+				if md, ok := metadata.FromIncomingContext(ctx); ok {
+					loggerContext.Add("tenant", md.Get("x-tenant")[0])
+				}
+				if pingRequest, ok := req.(*pb_testproto.PingRequest); ok {
+					loggerContext.Add("ping_value", pingRequest.Value)
+				}
+				return loggerContext
+			}))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	ctx := metadata.AppendToOutgoingContext(its.SimpleCtx(), "x-tenant", "my_tenant")
+	_, err := c.Ping(ctx, &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entryCtx := *entries[0].Context
+	assert.Equal(t, "/mwitkow.testproto.TestService/Ping", entryCtx["full_method"].Value)
+	assert.Equal(t, "my_tenant", entryCtx["tenant"].Value)
+	assert.Equal(t, "my_fake_ping_payload", entryCtx["ping_value"].Value)
+	assert.Equal(t, "Ping", entryCtx["grpc_method"].Value)
+}
+
+func TestUnaryInterceptor_WithFieldExtractors(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithFieldExtractors(
+				logger_grpc.ProtoFields(map[string]string{
+					"value":               "ping_value",
+					"error_code_returned": "ping_error_code",
+					"unknown.path":        "unknown",
+				}),
+				func(message interface{}) map[string]interface{} {
+					return map[string]interface{}{"message_type": fmt.Sprintf("%T", message)}
+				},
+			))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	entryCtx := *entries[0].Context
+	assert.Equal(t, "my_fake_ping_payload", entryCtx["ping_value"].Value)
+	assert.Equal(t, "*mwitkow_testproto.PingRequest", entryCtx["message_type"].Value)
+	assert.NotContains(t, entryCtx, "ping_error_code", "unset fields are not logged")
+	assert.NotContains(t, entryCtx, "unknown")
+}
+
+func TestUnaryInterceptor_WithPayloadHash(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithPayloadHash(nil))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	_, err := c.Ping(its.SimpleCtx(), pingRequest)
+	assert.NoError(t, err)
+	_, err = c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+	_, err = c.PingError(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload", ErrorCodeReturned: uint32(codes.NotFound)})
+	assert.Error(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 3)
+
+	data, err := proto.Marshal(pingRequest)
+	assert.NoError(t, err)
+	expectedHash := sha256.Sum256(data)
+
+	entry1Ctx := *entries[0].Context
+	assert.Equal(t, hex.EncodeToString(expectedHash[:]), entry1Ctx["grpc_request_data_hash"].Value)
+	assert.Equal(t, "mwitkow.testproto.PingRequest", entry1Ctx["grpc_request_data_type"].Value)
+	assert.Equal(t, "mwitkow.testproto.PingResponse", entry1Ctx["grpc_response_data_type"].Value)
+	assert.Contains(t, entry1Ctx, "grpc_response_data_hash")
+	assert.NotContains(t, entry1Ctx, "grpc_request_data")
+	assert.NotContains(t, entry1Ctx, "grpc_response_data")
+
+	entry2Ctx := *entries[1].Context
+	assert.Equal(t, entry1Ctx["grpc_request_data_hash"].Value, entry2Ctx["grpc_request_data_hash"].Value, "the hash is stable")
+
+	entry3Ctx := *entries[2].Context
+	assert.NotEqual(t, entry1Ctx["grpc_request_data_hash"].Value, entry3Ctx["grpc_request_data_hash"].Value)
+	assert.NotContains(t, entry3Ctx, "grpc_response_data_hash")
+}
+
+func TestUnaryInterceptor_WithKeyedPayloadHash(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithPayloadHash([]byte("my_secret_key")), logger_grpc.WithUnaryPayloads(true))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	pingRequest := &pb_testproto.PingRequest{Value: "my_fake_ping_payload"}
+	_, err := c.Ping(its.SimpleCtx(), pingRequest)
+	assert.NoError(t, err)
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+
+	data, err := proto.Marshal(pingRequest)
+	assert.NoError(t, err)
+	mac := hmac.New(sha256.New, []byte("my_secret_key"))
+	mac.Write(data)
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), (*entries[0].Context)["grpc_request_data_hash"].Value)
+}
+
+func TestUnaryInterceptor_WithPooledContexts(t *testing.T) {
+	var mu sync.Mutex
+	var entries []logger.Entry
+	// the pooled contexts are reused once logged, the handler must copy the entries it keeps
+	myLogger := logger.NewLogger(func(entry logger.Entry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		entry.Context = (&logger.Context{}).Merge(*entry.Context)
+		entries = append(entries, entry)
+		return nil
+	})
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger, logger_grpc.WithPooledContexts(true))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+	_, err = c.PingError(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload", ErrorCodeReturned: uint32(codes.NotFound)})
+	assert.Error(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, entries, 2)
+
+	entry1Ctx := *entries[0].Context
+	assert.Equal(t, "Ping", entry1Ctx["grpc_method"].Value)
+	assert.Equal(t, "OK", entry1Ctx["grpc_code"].Value)
+	assert.NotContains(t, entry1Ctx, "grpc_error")
+
+	entry2Ctx := *entries[1].Context
+	assert.Equal(t, "PingError", entry2Ctx["grpc_method"].Value)
+	assert.Equal(t, "NotFound", entry2Ctx["grpc_code"].Value)
+	assert.Contains(t, entry2Ctx, "grpc_error")
+	assert.Equal(t, "server", entry2Ctx["grpc_kind"].Value)
+}
+
+func TestUnaryInterceptor_WithAsyncLogger(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+	asyncLogger := logger_grpc.NewAsyncLogger(myLogger)
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(asyncLogger)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+	_, err = c.PingError(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload", ErrorCodeReturned: uint32(codes.NotFound)})
+	assert.Error(t, err)
+
+	asyncLogger.Flush()
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, logger.InfoLevel, entries[0].Level)
+	assert.Equal(t, "OK", (*entries[0].Context)["grpc_code"].Value)
+	assert.Equal(t, logger.InfoLevel, entries[1].Level)
+	assert.Equal(t, "NotFound", (*entries[1].Context)["grpc_code"].Value)
+
+	assert.NoError(t, asyncLogger.Close())
+	assert.Equal(t, logger_grpc.ErrAsyncLoggerClosed, asyncLogger.Info("closed", nil))
+	assert.Len(t, myLogger.GetEntries(), 2)
+}
+
+func TestUnaryInterceptor_WithRateLimit(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger,
+				logger_grpc.WithRateLimit(logger_grpc.ErrorRateLimit(logger_grpc.RateLimit{Rate: 0.001, Burst: 2})),
+				logger_grpc.WithRateLimitSummaryInterval(50*time.Millisecond),
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	for i := 0; i < 10; i++ {
+		_, err := c.PingError(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload", ErrorCodeReturned: uint32(codes.Unavailable)})
+		assert.Error(t, err)
+		_, err = c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+		assert.NoError(t, err)
+	}
+
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 12)
+	unavailable := 0
+	for _, e := range entries {
+		if (*e.Context)["grpc_code"].Value == "Unavailable" {
+			unavailable++
+		}
+	}
+	assert.Equal(t, 2, unavailable)
+
+	assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 13 }, time.Second, 10*time.Millisecond)
+	entries = myLogger.GetEntries()
+
+	summary := entries[12]
+	summaryCtx := *summary.Context
+	assert.Equal(t, logger.WarningLevel, summary.Level)
+	assert.Equal(t, "suppressed 8 Unavailable entries for /mwitkow.testproto.TestService/PingError in last 50ms", summary.Message)
+	assert.Equal(t, int64(8), summaryCtx["grpc_suppressed"].Value)
+	assert.Equal(t, "Unavailable", summaryCtx["grpc_code"].Value)
+	assert.Equal(t, "PingError", summaryCtx["grpc_method"].Value)
+	assert.Equal(t, "mwitkow.testproto.TestService", summaryCtx["grpc_service"].Value)
+	assert.Equal(t, "server", summaryCtx["grpc_kind"].Value)
+
+	assert.Never(t, func() bool { return len(myLogger.GetEntries()) > 13 }, 150*time.Millisecond, 10*time.Millisecond, "no summary without suppressed entries")
+}
+
+func TestUnaryInterceptor_WithRateLimitWithoutBurst(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(myLogger,
+				logger_grpc.WithRateLimit(logger_grpc.ErrorRateLimit(logger_grpc.RateLimit{Rate: 0.001})),
+				logger_grpc.WithRateLimitSummaryInterval(time.Hour),
+			)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+	defer its.TearDownSuite()
+
+	c := its.NewClient()
+	for i := 0; i < 3; i++ {
+		_, err := c.PingError(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload", ErrorCodeReturned: uint32(codes.Unavailable)})
+		assert.Error(t, err)
+	}
+
+	// a burst of 0 is handled as a burst of 1
+	entries := myLogger.GetEntries()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "Unavailable", (*entries[0].Context)["grpc_code"].Value)
+}
+
+// closeNotifierLogger wraps an AsyncLogger behind another logger and delegates its close notifications
+type closeNotifierLogger struct {
+	logger.LoggerInterface
+	asyncLogger *logger_grpc.AsyncLogger
+}
+
+func (l *closeNotifierLogger) OnClose(f func()) func() {
+	return l.asyncLogger.OnClose(f)
+}
+
+func TestUnaryInterceptor_WithRateLimitAndAsyncLoggerClose(t *testing.T) {
+	tests := []struct {
+		name   string
+		logger func(asyncLogger *logger_grpc.AsyncLogger) logger.LoggerInterface
+	}{
+		{
+			name: "async logger",
+			logger: func(asyncLogger *logger_grpc.AsyncLogger) logger.LoggerInterface {
+				return asyncLogger
+			},
+		},
+		{
+			name: "wrapped async logger",
+			logger: func(asyncLogger *logger_grpc.AsyncLogger) logger.LoggerInterface {
+				return &closeNotifierLogger{LoggerInterface: asyncLogger, asyncLogger: asyncLogger}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			myLogger := &testing_logger.Logger{}
+			asyncLogger := logger_grpc.NewAsyncLogger(myLogger)
+
+			its := &grpc_testing.InterceptorTestSuite{
+				ServerOpts: []grpc.ServerOption{
+					grpc.UnaryInterceptor(server_interceptor.UnaryInterceptor(tt.logger(asyncLogger),
+						logger_grpc.WithRateLimit(logger_grpc.ErrorRateLimit(logger_grpc.RateLimit{Rate: 0.001, Burst: 1})),
+						logger_grpc.WithRateLimitSummaryInterval(time.Hour),
+					)),
+				},
+			}
+			its.Suite.SetT(t)
+			its.SetupSuite()
+			defer its.TearDownSuite()
+
+			c := its.NewClient()
+			for i := 0; i < 3; i++ {
+				_, err := c.PingError(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload", ErrorCodeReturned: uint32(codes.Unavailable)})
+				assert.Error(t, err)
+			}
+
+			// the pending summary is logged on close instead of the next summary interval
+			assert.NoError(t, asyncLogger.Close())
+			entries := myLogger.GetEntries()
+			assert.Len(t, entries, 2)
+			assert.Equal(t, "suppressed 2 Unavailable entries for /mwitkow.testproto.TestService/PingError in last 1h0m0s", entries[1].Message)
+			assert.Equal(t, int64(2), (*entries[1].Context)["grpc_suppressed"].Value)
+		})
+	}
+}
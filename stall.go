@@ -0,0 +1,82 @@
+package logger_grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/gol4ng/logger"
+)
+
+func noop() {}
+
+// StartIdleDetection logs a warning when no message was sent or received during the stream idle timeout,
+// once per idle period, until the returned stop function is called or the context is done.
+// The snapshot function must return a copy of the current stream call info.
+func StartIdleDetection(ctx context.Context, log logger.LoggerInterface, loggerContext *logger.Context, o *Options, snapshot func() CallInfo) (stop func()) {
+	if o.StreamIdleTimeout <= 0 {
+		return noop
+	}
+	hook := NewLoggingHook(log, loggerContext, o)
+	var warnedIdleSince time.Time
+	// the idle period is checked 4 times per timeout, a timeout under 4ns gives a zero interval a ticker rejects
+	interval := o.StreamIdleTimeout / 4
+	if interval <= 0 {
+		interval = o.StreamIdleTimeout
+	}
+	return startTicker(ctx, interval, func(now time.Time) {
+		info := snapshot()
+		idleSince := info.idleSince(info.LastMessageTime)
+		if now.Sub(idleSince) < o.StreamIdleTimeout || idleSince.Equal(warnedIdleSince) {
+			return
+		}
+		warnedIdleSince = idleSince
+		hook.idle(&info, now)
+	})
+}
+
+// WatchSendStall logs a warning when a single send blocks longer than the send stall threshold,
+// the returned done function must be called once the send returns.
+// The snapshot function must return a copy of the current stream call info.
+func WatchSendStall(log logger.LoggerInterface, loggerContext *logger.Context, o *Options, snapshot func() CallInfo) (done func()) {
+	if o.SendStallThreshold <= 0 {
+		return noop
+	}
+	startTime := time.Now()
+	logged := make(chan struct{})
+	timer := time.AfterFunc(o.SendStallThreshold, func() {
+		defer close(logged)
+		info := snapshot()
+		NewLoggingHook(log, loggerContext, o).sendStall(&info, time.Since(startTime))
+	})
+	return func() {
+		if !timer.Stop() {
+			<-logged
+		}
+	}
+}
+
+// idle logs the stream inactivity
+func (h *LoggingHook) idle(info *CallInfo, now time.Time) {
+	idle := now.Sub(info.idleSince(info.LastMessageTime))
+	ctx := h.entryContext(info).
+		Add("grpc_stream_elapsed", h.options.FormatDuration(now.Sub(info.StartTime))).
+		Add("grpc_stream_idle", h.options.FormatDuration(idle)).
+		Add("grpc_stream_send_idle", h.options.FormatDuration(now.Sub(info.idleSince(info.LastSentTime)))).
+		Add("grpc_stream_receive_idle", h.options.FormatDuration(now.Sub(info.idleSince(info.LastReceivedTime))))
+
+	event := h.event(EventStreamIdle, info)
+	event.Duration = idle
+	h.logEntry(event, logger.WarningLevel, ctx)
+}
+
+// sendStall logs the blocked send
+func (h *LoggingHook) sendStall(info *CallInfo, blocked time.Duration) {
+	ctx := h.entryContext(info).
+		Add("grpc_stall_direction", "send").
+		Add("grpc_stall_duration", h.options.FormatDuration(blocked)).
+		Add("grpc_stream_elapsed", h.options.FormatDuration(time.Since(info.StartTime)))
+
+	event := h.event(EventSendStall, info)
+	event.Duration = blocked
+	h.logEntry(event, logger.WarningLevel, ctx)
+}
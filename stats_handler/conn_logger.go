@@ -0,0 +1,99 @@
+package stats_handler
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/gol4ng/logger"
+	"google.golang.org/grpc/stats"
+
+	logger_grpc "github.com/gol4ng/logger-grpc"
+)
+
+type connStatsKey struct{}
+
+type connStats struct {
+	rpcCount      int64
+	startTime     time.Time
+	remoteAddr    string
+	loggerContext *logger.Context
+}
+
+// ConnHandler is a grpc stats.Handler that log the connections lifecycle
+type ConnHandler struct {
+	options *logger_grpc.Options
+	logger  logger.LoggerInterface
+}
+
+// TagRPC count the rpc made on the tagged connection
+func (h *ConnHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	if cs, ok := ctx.Value(connStatsKey{}).(*connStats); ok {
+		atomic.AddInt64(&cs.rpcCount, 1)
+	}
+	return ctx
+}
+
+func (h *ConnHandler) HandleRPC(context.Context, stats.RPCStats) {}
+
+// TagConn attach the connection stats to the connection context when the connection should be logged
+func (h *ConnHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	if !h.options.ConnDecider(info) {
+		return ctx
+	}
+	startTime := time.Now()
+	return context.WithValue(ctx, connStatsKey{}, &connStats{
+		startTime:     startTime,
+		remoteAddr:    addrString(info.RemoteAddr),
+		loggerContext: feedConnContext(info, startTime, h.options),
+	})
+}
+
+// HandleConn log the connection begin and end
+func (h *ConnHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	cs, ok := ctx.Value(connStatsKey{}).(*connStats)
+	if !ok {
+		return
+	}
+	switch s.(type) {
+	case *stats.ConnBegin:
+		_ = h.logger.Info(h.options.FormatMessage(&logger_grpc.CallEvent{Type: logger_grpc.EventConnBegin, Kind: "server", RemoteAddr: cs.remoteAddr}), h.options.ApplySchema(cs.loggerContext))
+	case *stats.ConnEnd:
+		duration := time.Since(cs.startTime)
+		rpcCount := atomic.LoadInt64(&cs.rpcCount)
+		loggerContext := h.options.AcquireContext().Merge(*cs.loggerContext).
+			Add("grpc_conn_duration", h.options.FormatDuration(duration)).
+			Add("grpc_conn_rpc_count", rpcCount)
+		_ = h.logger.Info(h.options.FormatMessage(&logger_grpc.CallEvent{Type: logger_grpc.EventConnEnd, Kind: "server", RemoteAddr: cs.remoteAddr, RPCCount: rpcCount, Duration: duration}), h.options.ApplySchema(loggerContext))
+		h.options.ReleaseContext(loggerContext)
+	}
+}
+
+func feedConnContext(info *stats.ConnTagInfo, startTime time.Time, o *logger_grpc.Options) *logger.Context {
+	loggerContext := logger.NewContext().
+		Add("grpc_kind", "server").
+		Add("grpc_conn_start_time", o.FormatTime(startTime))
+	if info.RemoteAddr != nil {
+		loggerContext.Add("grpc_remote_addr", info.RemoteAddr.String())
+	}
+	if info.LocalAddr != nil {
+		loggerContext.Add("grpc_local_addr", info.LocalAddr.String())
+	}
+	return loggerContext
+}
+
+func addrString(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	return addr.String()
+}
+
+// ServerHandler returns a new server stats handler that log the connections lifecycle.
+func ServerHandler(log logger.LoggerInterface, opts ...logger_grpc.Option) stats.Handler {
+	return &ConnHandler{
+		options: logger_grpc.EvaluateServerOpt(opts),
+		logger:  log,
+	}
+}
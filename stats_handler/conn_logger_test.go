@@ -0,0 +1,93 @@
+package stats_handler_test
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gol4ng/logger"
+	testing_logger "github.com/gol4ng/logger/testing"
+	grpc_testing "github.com/grpc-ecosystem/go-grpc-middleware/testing"
+	pb_testproto "github.com/grpc-ecosystem/go-grpc-middleware/testing/testproto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+
+	logger_grpc "github.com/gol4ng/logger-grpc"
+	"github.com/gol4ng/logger-grpc/stats_handler"
+)
+
+func TestServerHandler(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StatsHandler(stats_handler.ServerHandler(myLogger)),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+
+	its.TearDownSuite()
+	// the test suite dial its own client connection in addition to ours, each logs a begin and an end entry
+	assert.Eventually(t, func() bool { return len(myLogger.GetEntries()) == 4 }, time.Second, time.Millisecond, "time until all connections closed")
+
+	var beginEntries, endEntries []logger.Entry
+	for _, e := range myLogger.GetEntries() {
+		eCtx := *e.Context
+		assert.Equal(t, "server", eCtx["grpc_kind"].Value)
+		assert.Contains(t, eCtx, "grpc_conn_start_time")
+		assert.Contains(t, eCtx, "grpc_remote_addr")
+		assert.Equal(t, its.ServerAddr(), eCtx["grpc_local_addr"].Value)
+
+		switch {
+		case strings.HasPrefix(e.Message, "grpc server connection begin"):
+			assert.Equal(t, logger.InfoLevel, e.Level)
+			beginEntries = append(beginEntries, e)
+		case strings.HasPrefix(e.Message, "grpc server connection end"):
+			assert.Equal(t, logger.InfoLevel, e.Level)
+			assert.Contains(t, eCtx, "grpc_conn_duration")
+			endEntries = append(endEntries, e)
+		}
+	}
+	assert.Len(t, beginEntries, 2)
+	assert.Len(t, endEntries, 2)
+
+	rpcCount := int64(0)
+	for _, e := range endEntries {
+		rpcCount += (*e.Context)["grpc_conn_rpc_count"].Value.(int64)
+		assert.Regexp(t, `grpc server connection end 127\.0\.0\.1:\d+ \[rpc_count:\d+, duration:.*\]`, e.Message)
+	}
+	assert.Equal(t, int64(1), rpcCount)
+}
+
+func TestServerHandler_WithConnDecider(t *testing.T) {
+	myLogger := &testing_logger.Logger{}
+	decided := int64(0)
+
+	its := &grpc_testing.InterceptorTestSuite{
+		ServerOpts: []grpc.ServerOption{
+			grpc.StatsHandler(stats_handler.ServerHandler(myLogger, logger_grpc.WithConnDecider(func(info *stats.ConnTagInfo) bool {
+				atomic.AddInt64(&decided, 1)
+				return false
+			}))),
+		},
+	}
+	its.Suite.SetT(t)
+	its.SetupSuite()
+
+	c := its.NewClient()
+	_, err := c.Ping(its.SimpleCtx(), &pb_testproto.PingRequest{Value: "my_fake_ping_payload"})
+	assert.NoError(t, err)
+
+	its.TearDownSuite()
+	// the connections skipped by the decider never log, even once closed
+	assert.Eventually(t, func() bool { return atomic.LoadInt64(&decided) == 2 }, time.Second, time.Millisecond)
+
+	assert.Empty(t, myLogger.GetEntries())
+}
@@ -0,0 +1,92 @@
+package logger_grpc
+
+import (
+	"github.com/gol4ng/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// StatusDetailRenderer function adds the given status detail to the logger context,
+// it returns false when the detail type is not handled.
+type StatusDetailRenderer func(loggerContext *logger.Context, detail interface{}) bool
+
+// DefaultStatusDetailRenderer renders the google.rpc error details as structured fields
+func DefaultStatusDetailRenderer(loggerContext *logger.Context, detail interface{}) bool {
+	switch d := detail.(type) {
+	case *errdetails.ErrorInfo:
+		loggerContext.
+			Add("grpc_error_reason", d.GetReason()).
+			Add("grpc_error_domain", d.GetDomain())
+		if len(d.GetMetadata()) > 0 {
+			loggerContext.Add("grpc_error_metadata", d.GetMetadata())
+		}
+	case *errdetails.BadRequest:
+		violations := make([]map[string]string, 0, len(d.GetFieldViolations()))
+		for _, v := range d.GetFieldViolations() {
+			violations = append(violations, map[string]string{"field": v.GetField(), "description": v.GetDescription()})
+		}
+		loggerContext.Add("grpc_error_field_violations", violations)
+	case *errdetails.RetryInfo:
+		if delay := d.GetRetryDelay(); delay != nil {
+			loggerContext.Add("grpc_error_retry_delay", float64(delay.GetSeconds())+float64(delay.GetNanos())/1e9)
+		}
+	case *errdetails.QuotaFailure:
+		violations := make([]map[string]string, 0, len(d.GetViolations()))
+		for _, v := range d.GetViolations() {
+			violations = append(violations, map[string]string{"subject": v.GetSubject(), "description": v.GetDescription()})
+		}
+		loggerContext.Add("grpc_error_quota_violations", violations)
+	case *errdetails.PreconditionFailure:
+		violations := make([]map[string]string, 0, len(d.GetViolations()))
+		for _, v := range d.GetViolations() {
+			violations = append(violations, map[string]string{"type": v.GetType(), "subject": v.GetSubject(), "description": v.GetDescription()})
+		}
+		loggerContext.Add("grpc_error_precondition_violations", violations)
+	case *errdetails.DebugInfo:
+		loggerContext.
+			Add("grpc_error_debug_detail", d.GetDetail()).
+			Add("grpc_error_debug_stack", d.GetStackEntries())
+	case *errdetails.RequestInfo:
+		loggerContext.
+			Add("grpc_error_request_id", d.GetRequestId()).
+			Add("grpc_error_serving_data", d.GetServingData())
+	case *errdetails.ResourceInfo:
+		loggerContext.Add("grpc_error_resource", map[string]string{
+			"type":        d.GetResourceType(),
+			"name":        d.GetResourceName(),
+			"owner":       d.GetOwner(),
+			"description": d.GetDescription(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+// FeedStatusDetails adds the error status details to the logger context,
+// the details not handled by any StatusDetailRenderer are added as is
+func FeedStatusDetails(loggerContext *logger.Context, err error, o *Options) *logger.Context {
+	st, ok := status.FromError(err)
+	if !ok || st == nil {
+		return loggerContext
+	}
+	var unhandled []interface{}
+	for _, detail := range st.Details() {
+		if !renderStatusDetail(loggerContext, detail, o.StatusDetailRenderers) {
+			unhandled = append(unhandled, detail)
+		}
+	}
+	if len(unhandled) > 0 {
+		loggerContext.Add("grpc_error_details", unhandled)
+	}
+	return loggerContext
+}
+
+func renderStatusDetail(loggerContext *logger.Context, detail interface{}, renderers []StatusDetailRenderer) bool {
+	for _, render := range renderers {
+		if render(loggerContext, detail) {
+			return true
+		}
+	}
+	return false
+}